@@ -0,0 +1,76 @@
+package cliff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// annotationAliases is the [pflag.Flag] annotation with the names of the flag aliases.
+const annotationAliases = "cliff-aliases"
+
+// annotationAliasOf is the [pflag.Flag] annotation of the alias flag
+// with the name of the flag it is an alias of.
+const annotationAliasOf = "cliff-alias-of"
+
+// addAliases adds hidden flags sharing the value with the flag with the given name.
+func addAliases(fs *pflag.FlagSet, name string, aliases []string) error {
+	pf := fs.Lookup(name)
+	for _, alias := range aliases {
+		if fs.Lookup(alias) != nil {
+			return fmt.Errorf("flag %s is already defined", alias)
+		}
+		fs.VarP(pf.Value, alias, "", pf.Usage)
+		af := fs.Lookup(alias)
+		af.NoOptDefVal = pf.NoOptDefVal
+		af.Hidden = true
+		af.Deprecated = pf.Deprecated
+		af.Annotations = map[string][]string{annotationAliasOf: {name}}
+	}
+	return fs.SetAnnotation(name, annotationAliases, aliases)
+}
+
+// resolveAliases marks flags as passed if any of their aliases is passed.
+func resolveAliases(pfs *pflag.FlagSet) {
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		if pf.Changed && pf.Annotations[annotationAliasOf] != nil {
+			pfs.Lookup(pf.Annotations[annotationAliasOf][0]).Changed = true
+		}
+	})
+}
+
+// ownerOf returns the flag the given alias or negation belongs to
+// or the given flag itself.
+func ownerOf(pfs *pflag.FlagSet, pf *pflag.Flag) *pflag.Flag {
+	name := pf.Annotations[annotationAliasOf]
+	if name == nil {
+		name = pf.Annotations[annotationNegates]
+	}
+	if name == nil {
+		return pf
+	}
+	return pfs.Lookup(name[0])
+}
+
+// companions returns the flag together with its aliases and negation.
+func companions(pfs *pflag.FlagSet, pf *pflag.Flag) []*pflag.Flag {
+	flags := []*pflag.Flag{pf}
+	for _, alias := range pf.Annotations[annotationAliases] {
+		flags = append(flags, pfs.Lookup(alias))
+	}
+	neg := pf.Annotations[annotationNegatable]
+	if neg != nil {
+		flags = append(flags, pfs.Lookup(neg[0]))
+	}
+	return flags
+}
+
+// aliasNames returns the aliases of the flag with dashes, like ", --addr".
+func aliasNames(pf *pflag.Flag) string {
+	aliases := pf.Annotations[annotationAliases]
+	if len(aliases) == 0 {
+		return ""
+	}
+	return ", --" + strings.Join(aliases, ", --")
+}
@@ -0,0 +1,126 @@
+package cliff_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestAlias(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		listen string
+		result cliff.Result
+	}
+	init := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"listen": cliff.F(&c.listen, 'l', ":8080", "address to listen on").Alias("addr", "bind"),
+			},
+			Result: &c.result,
+		}
+	}
+	parse := func(args ...string) (Config, error) {
+		return cliff.Parse(io.Discard, append([]string{"example"}, args...), init)
+	}
+
+	c, err := parse("--addr", ":80")
+	is.NoErr(err)
+	is.Equal(c.listen, ":80")
+	is.Equal(c.result.Source("listen"), cliff.SourceCLI)
+
+	c, err = parse("--bind=:81")
+	is.NoErr(err)
+	is.Equal(c.listen, ":81")
+
+	c, err = parse("-l", ":82")
+	is.NoErr(err)
+	is.Equal(c.listen, ":82")
+
+	var help bytes.Buffer
+	_, _ = cliff.Parse(&help, []string{"example", "--help"}, init)
+	is.Equal(help.String(), "Usage: example [flags]\n\nFlags:\n  -l, --listen, --addr, --bind string   address to listen on (default \":8080\")\n")
+}
+
+func TestAlias_Negatable(t *testing.T) {
+	is := is.New(t)
+
+	var color bool
+	flags := cliff.Flags{"color": cliff.F(&color, 0, true, "").Alias("colour").Negatable()}
+	err := flags.Parse(io.Discard, []string{"example", "--colour=false"})
+	is.NoErr(err)
+	is.Equal(color, false)
+
+	err = flags.Parse(io.Discard, []string{"example", "--colour", "--no-color"})
+	is.Equal(err.Error(), "flags --color and --no-color cannot be used together")
+}
+
+func TestAlias_Persistent(t *testing.T) {
+	is := is.New(t)
+
+	var listen string
+	root := cliff.Cmd(func(c *struct{}) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"listen": cliff.F(&listen, 0, "", "").Alias("addr").Persistent(),
+			},
+			Commands: cliff.Commands{
+				"serve": cliff.Cmd(func(c *struct{}) cliff.Flags {
+					return cliff.Flags{}
+				}, func(struct{}) error { return nil }, ""),
+			},
+		}
+	}, func(struct{}) error { return nil }, "")
+	err := cliff.Run(io.Discard, []string{"example", "serve", "--addr", ":80"}, root)
+	is.NoErr(err)
+	is.Equal(listen, ":80")
+}
+
+func TestAlias_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var a, b string
+	var n bool
+	cases := []struct {
+		flags cliff.Flags
+		err   string
+	}{
+		{
+			cliff.Flags{"a": cliff.F(&a, 0, "", "").Alias("B")},
+			"validate alias (B) of flag a: must be lowercase",
+		},
+		{
+			cliff.Flags{"a": cliff.F(&a, 0, "", "").Alias("b"), "b": cliff.F(&b, 0, "", "")},
+			"conflicting flags: --b is defined by alias of --a and flag --b",
+		},
+		{
+			cliff.Flags{"a": cliff.F(&a, 0, "", "").Alias("c"), "b": cliff.F(&b, 0, "", "").Alias("c")},
+			"conflicting flags: --c is defined by alias of --a and alias of --b",
+		},
+		{
+			cliff.Flags{"a": cliff.F(&a, 0, "", "").Alias("no-n"), "n": cliff.F(&n, 0, false, "").Negatable()},
+			"conflicting flags: --no-n is defined by alias of --a and negation of --n",
+		},
+	}
+	for _, c := range cases {
+		_, err := c.flags.PFlagSet(io.Discard, "example")
+		is.True(err != nil)
+		is.Equal(err.Error(), c.err)
+	}
+}
+
+func TestAlias_Completion(t *testing.T) {
+	is := is.New(t)
+
+	var listen string
+	flags := cliff.Flags{"listen": cliff.F(&listen, 0, "", "").Alias("addr")}
+	var buf bytes.Buffer
+	err := flags.Completion(&buf, cliff.Fish, "example")
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), "-l addr"))
+}
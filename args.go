@@ -0,0 +1,166 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Args is a list of positional CLI arguments in the order they are expected.
+type Args []Arg
+
+// Arg represents all info about a positional CLI argument.
+type Arg struct {
+	setter   tPFlag
+	name     string
+	required bool // the argument must be provided
+	variadic bool // the argument consumes all remaining values
+}
+
+// A creates a new positional argument.
+//
+// The name is used only in help and error messages.
+// The argument is optional unless marked as [Arg.Required].
+func A[T Constraint](val *T, name string, def T, help Help) Arg {
+	setter := tPFlag{
+		tar:  target(val),
+		def:  def,
+		help: string(help),
+	}
+	return Arg{setter: setter, name: name}
+}
+
+// Required makes the argument mandatory.
+//
+// If the argument is also [Arg.Variadic], at least one value must be provided.
+func (a Arg) Required() Arg {
+	a.required = true
+	return a
+}
+
+// Variadic makes the argument to consume all the remaining positional arguments.
+//
+// Only the last argument can be variadic and its type must be a slice.
+// Every value is parsed separately, as if the flag of the same type
+// was passed multiple times.
+func (a Arg) Variadic() Arg {
+	a.variadic = true
+	return a
+}
+
+// metavar is the argument name as shown in help and errors.
+func (a Arg) metavar() string {
+	name := strings.ToUpper(a.name)
+	if a.variadic {
+		name += "..."
+	}
+	return name
+}
+
+// value creates the [pflag.Value] that will write the parsed argument into the target.
+func (a Arg) value() (pflag.Value, error) {
+	fs := pflag.NewFlagSet(a.name, pflag.ContinueOnError)
+	// Variadic strings are not split on commas because
+	// every argument is already a separate value.
+	if v, ok := a.setter.tar.(*[]string); ok && a.variadic {
+		def, _ := a.setter.def.([]string)
+		fs.StringArrayVar(v, a.name, def, "")
+	} else {
+		err := a.setter.pflagAddFlag(a.name, fs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	val := fs.Lookup(a.name).Value
+	if a.variadic && !isSlice(val) {
+		return nil, errors.New("variadic argument must be a slice")
+	}
+	return val, nil
+}
+
+// validate checks that the arguments can be unambiguously parsed.
+func (as Args) validate() error {
+	optional := ""
+	for i, a := range as {
+		err := validateName(a.name)
+		if err != nil {
+			return fmt.Errorf("validate argument name (%s): %v", a.name, err)
+		}
+		if a.variadic && i != len(as)-1 {
+			return fmt.Errorf("argument %s: only the last argument can be variadic", a.name)
+		}
+		if a.required && optional != "" {
+			return fmt.Errorf("argument %s: required argument after optional %s", a.name, optional)
+		}
+		if !a.required {
+			optional = a.name
+		}
+	}
+	return nil
+}
+
+// values creates [pflag.Value] for each argument and sets default values.
+func (as Args) values() ([]pflag.Value, error) {
+	err := as.validate()
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]pflag.Value, len(as))
+	for i, a := range as {
+		vals[i], err = a.value()
+		if err != nil {
+			return nil, fmt.Errorf("add argument %s: %v", a.name, err)
+		}
+	}
+	return vals, nil
+}
+
+// parse the given positional arguments into values created by [Args.values].
+func (as Args) parse(vals []pflag.Value, args []string) error {
+	for i, a := range as {
+		if len(args) == 0 {
+			return as[i:].missing()
+		}
+		raw := args[:1]
+		if a.variadic {
+			raw = args
+		}
+		args = args[len(raw):]
+		for _, r := range raw {
+			err := vals[i].Set(r)
+			if err != nil {
+				return InvalidValueError{
+					Name:  a.metavar(),
+					Value: r,
+					Err:   err,
+					msg:   fmt.Sprintf("invalid argument %q for %s: %v", r, a.metavar(), err),
+				}
+			}
+		}
+	}
+	if len(args) != 0 {
+		return UnexpectedArgsError{Args: args}
+	}
+	return nil
+}
+
+// missing returns [MissingArgsError] for all required arguments, if any.
+func (as Args) missing() error {
+	names := make([]string, 0)
+	for _, a := range as {
+		if a.required {
+			names = append(names, a.metavar())
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return MissingArgsError{Names: names}
+}
+
+func isSlice(val pflag.Value) bool {
+	t := val.Type()
+	return strings.HasSuffix(t, "Slice") || strings.HasSuffix(t, "Array")
+}
@@ -0,0 +1,72 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestArgs(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		port  int
+		src   string
+		dst   string
+		files []string
+	}
+	var config Config
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"port": cliff.F(&config.port, 'p', 8080, "port to listen to"),
+		},
+		Args: cliff.Args{
+			cliff.A(&config.src, "src", "", "").Required(),
+			cliff.A(&config.dst, "dst", ".", ""),
+			cliff.A(&config.files, "files", nil, "").Variadic(),
+		},
+	}
+
+	err := spec.Parse(io.Discard, []string{"example", "a", "-p", "80", "b", "c", "d"})
+	is.NoErr(err)
+	is.Equal(config, Config{port: 80, src: "a", dst: "b", files: []string{"c", "d"}})
+
+	err = spec.Parse(io.Discard, []string{"example", "a"})
+	is.NoErr(err)
+	is.Equal(config, Config{port: 8080, src: "a", dst: ".", files: nil})
+
+	err = spec.Parse(io.Discard, []string{"example", "-p", "80"})
+	is.Equal(err.Error(), "missing required argument: SRC")
+}
+
+func TestArgs_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var n int
+	var s string
+	parse := func(args cliff.Args, raw ...string) error {
+		spec := cliff.Spec{Args: args}
+		return spec.Parse(io.Discard, append([]string{"example"}, raw...))
+	}
+
+	err := parse(cliff.Args{cliff.A(&n, "n", 0, "")}, "hi")
+	is.Equal(err.Error(), `invalid argument "hi" for N: strconv.ParseInt: parsing "hi": invalid syntax`)
+
+	err = parse(cliff.Args{cliff.A(&n, "n", 0, "")}, "1", "2")
+	is.Equal(err.Error(), "unexpected argument: 2")
+
+	err = parse(cliff.Args{cliff.A(&n, "n", 0, "").Variadic()}, "1")
+	is.Equal(err.Error(), "add argument n: variadic argument must be a slice")
+
+	err = parse(cliff.Args{cliff.A(&s, "s", "", ""), cliff.A(&n, "n", 0, "").Required()})
+	is.Equal(err.Error(), "argument n: required argument after optional s")
+
+	err = parse(cliff.Args{cliff.A(&s, "S", "", "")})
+	is.Equal(err.Error(), "validate argument name (S): must be lowercase")
+
+	// If Args is nil, positional arguments are ignored.
+	err = parse(nil, "a", "b")
+	is.NoErr(err)
+}
@@ -0,0 +1,218 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Commands is a mapping of subcommand names to the subcommands.
+type Commands map[string]Command
+
+// Command is a CLI subcommand with its own flags, arguments, and handler.
+type Command struct {
+	runner runner
+	help   string // short description shown in the list of commands
+}
+
+type runner interface {
+	run(stderr io.Writer, args []string, inh inherited) error
+	spec() Spec
+}
+
+// inherited is the state passed from the parent command into the subcommand.
+type inherited struct {
+	flags []*pflag.Flag          // persistent flags of all parent commands
+	set   map[*pflag.Flag]Source // flags set from env vars or config files
+
+	// validators for persistent flags of all parent commands
+	checks map[*pflag.Flag]func() error
+
+	// groups with persistent flags of parent commands
+	groups []resolvedGroup
+
+	// set persistent flags from env vars and config files of parent commands.
+	// Called only after the last subcommand is parsed, so that flags passed
+	// after the subcommand name replace env values instead of appending to them.
+	sources []func() error
+
+	// update [Spec.Result] of parent commands after all values are applied
+	results []func()
+
+	// where the root command records its [Spec.ExitCodes] for [MustRun]
+	exitCodes *ExitCodes
+}
+
+// Cmd creates a new subcommand.
+//
+// When the command is selected, its arguments are parsed into a new T
+// and the handler is called with the result. If the returned [Spec] has subcommands,
+// the handler is called only if no subcommand is selected. In that case, the handler can be nil.
+//
+// The handler of a subcommand can access the config of the parent command
+// if the subcommand is defined inside of the parent's init function.
+func Cmd[T any, D Definition](init func(c *T) D, handler func(T) error, help Help) Command {
+	r := tCommand[T, D]{init: init, handler: handler}
+	return Command{runner: r, help: string(help)}
+}
+
+// Run parses the arguments and calls the handler of the selected command.
+//
+// Help and warnings will be written into the given stderr stream.
+// The error returned by the handler is passed through.
+//
+// Typical usage:
+//
+//	err := cliff.Run(os.Stderr, os.Args, cmd)
+func Run(stderr io.Writer, args []string, cmd Command) error {
+	return cmd.runner.run(stderr, args, inherited{})
+}
+
+// MustRun is like [Run] but writes errors into stderr and exits on error or help.
+//
+// The ExitCodes of the command's [Spec] are used.
+//
+// Typical usage:
+//
+//	cliff.MustRun(os.Stderr, os.Exit, os.Args, cmd)
+func MustRun(stderr io.Writer, exit func(int), args []string, cmd Command) {
+	var codes ExitCodes
+	err := cmd.runner.run(stderr, args, inherited{exitCodes: &codes})
+	codes.HandleError(stderr, exit, err)
+}
+
+type tCommand[T any, D Definition] struct {
+	init    func(c *T) D
+	handler func(T) error
+}
+
+func (c tCommand[T, D]) run(stderr io.Writer, args []string, inh inherited) error {
+	var config T
+	spec := toSpec(c.init(&config))
+	if inh.exitCodes != nil {
+		*inh.exitCodes = spec.ExitCodes
+	}
+	sub, err := spec.parse(stderr, args, inh)
+	if err != nil {
+		return err
+	}
+	if sub != nil {
+		return sub.run()
+	}
+	if c.handler == nil {
+		return MissingCommandError{Commands: spec.Commands.names()}
+	}
+	return c.handler(config)
+}
+
+// spec returns the definition of the command without parsing anything.
+func (c tCommand[T, D]) spec() Spec {
+	var config T
+	return toSpec(c.init(&config))
+}
+
+// subcall is a subcommand selected when parsing arguments.
+type subcall struct {
+	stderr    io.Writer
+	cmd       Command
+	args      []string
+	inherited inherited
+}
+
+func (s subcall) run() error {
+	return s.cmd.runner.run(s.stderr, s.args, s.inherited)
+}
+
+// selectCommand finds the subcommand to run in arguments left after parsing the flags.
+//
+// Returns nil if there are no arguments left.
+func (s Spec) selectCommand(stderr io.Writer, name string, pfs *pflag.FlagSet, inh inherited) (*subcall, error) {
+	rest := pfs.Args()
+	if len(rest) == 0 {
+		return nil, nil
+	}
+	cmd, found := s.Commands[rest[0]]
+	if !found {
+		return nil, UnknownCommandError{Name: rest[0], Suggestions: suggestTop(rest[0], s.Commands.names())}
+	}
+	flags := append([]*pflag.Flag{}, inh.flags...)
+	for _, fname := range s.Flags.names() {
+		if s.Flags[fname].persist {
+			flags = append(flags, companions(pfs, pfs.Lookup(fname))...)
+		}
+	}
+	sub := &subcall{
+		stderr: stderr,
+		cmd:    cmd,
+		args:   append([]string{name + " " + rest[0]}, rest[1:]...),
+		inherited: inherited{
+			flags:   flags,
+			set:     inh.set,
+			checks:  inh.checks,
+			sources: inh.sources,
+			results: inh.results,
+		},
+	}
+	return sub, nil
+}
+
+// validate checks that all subcommand names are valid.
+func (cs Commands) validate() error {
+	for name, cmd := range cs {
+		err := validateName(name)
+		if err != nil {
+			return fmt.Errorf("validate command name (%s): %v", name, err)
+		}
+		if cmd.runner == nil {
+			return fmt.Errorf("command %s is not initialized", name)
+		}
+	}
+	return nil
+}
+
+// names returns sorted names of all subcommands.
+func (cs Commands) names() []string {
+	names := make([]string, 0, len(cs))
+	for name := range cs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// inherit adds persistent flags of the parent command into the flag set.
+//
+// All shorthand conflicts are reported in a single error.
+func inherit(pfs *pflag.FlagSet, inherited []*pflag.Flag) error {
+	redefined := make(map[string]bool)
+	conflicts := make([]string, 0)
+	for _, pf := range inherited {
+		// The flag is redefined in the subcommand.
+		if pfs.Lookup(pf.Name) != nil {
+			redefined[pf.Name] = true
+			continue
+		}
+		// Aliases and negations of redefined flags are not inherited.
+		owner := pf.Annotations[annotationAliasOf]
+		if owner == nil {
+			owner = pf.Annotations[annotationNegates]
+		}
+		if owner != nil && redefined[owner[0]] {
+			continue
+		}
+		if pf.Shorthand != "" && pfs.ShorthandLookup(pf.Shorthand) != nil {
+			msg := fmt.Sprintf("shorthand -%s of persistent flag --%s is already used", pf.Shorthand, pf.Name)
+			conflicts = append(conflicts, msg)
+			continue
+		}
+		pfs.AddFlag(pf)
+	}
+	if len(conflicts) != 0 {
+		return errors.New(strings.Join(conflicts, "; "))
+	}
+	return nil
+}
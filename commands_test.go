@@ -0,0 +1,102 @@
+package cliff_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestCmd(t *testing.T) {
+	is := is.New(t)
+
+	type Global struct{ debug bool }
+	type Sub struct {
+		port int
+		name string
+	}
+	var global Global
+	var called []Sub
+	handler := func(c Sub) error {
+		called = append(called, c)
+		return nil
+	}
+	root := cliff.Cmd(func(g *Global) cliff.Spec {
+		global = Global{}
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"debug": cliff.F(&g.debug, 'd', false, "").Persistent(),
+				"local": cliff.F(new(bool), 0, false, ""),
+			},
+			Commands: cliff.Commands{
+				"serve": cliff.Cmd(func(c *Sub) cliff.Spec {
+					return cliff.Spec{
+						Flags: cliff.Flags{
+							"port": cliff.F(&c.port, 'p', 8080, ""),
+						},
+						Args: cliff.Args{
+							cliff.A(&c.name, "name", "", "").Required(),
+						},
+					}
+				}, handler, ""),
+			},
+		}
+	}, func(g Global) error {
+		global = g
+		return errors.New("root called")
+	}, "")
+
+	run := func(args ...string) error {
+		return cliff.Run(io.Discard, append([]string{"example"}, args...), root)
+	}
+
+	is.NoErr(run("serve", "-p", "80", "srv"))
+	is.NoErr(run("-d", "serve", "srv2"))
+	is.NoErr(run("serve", "srv3", "--debug"))
+	is.Equal(called, []Sub{{80, "srv"}, {8080, "srv2"}, {8080, "srv3"}})
+
+	is.Equal(run("--debug").Error(), "root called")
+	is.Equal(global, Global{debug: true})
+
+	is.Equal(run("serve").Error(), "missing required argument: NAME")
+	is.Equal(run("serve", "--local", "srv").Error(), "unknown flag: --local")
+	is.Equal(run("srv").Error(), "unknown command: srv; did you mean serve?")
+}
+
+func TestCmd_Errors(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct{ n int }
+	noop := func(Config) error { return nil }
+	leaf := cliff.Cmd(func(c *Config) cliff.Flags {
+		return cliff.Flags{"num": cliff.F(&c.n, 'n', 0, "")}
+	}, noop, "")
+	run := func(spec cliff.Spec, args ...string) error {
+		return spec.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+
+	err := run(cliff.Spec{Commands: cliff.Commands{"leaf": leaf}})
+	is.Equal(err.Error(), "missing command")
+
+	err = run(cliff.Spec{Commands: cliff.Commands{"Leaf": leaf}}, "Leaf")
+	is.Equal(err.Error(), "validate command name (Leaf): must be lowercase")
+
+	err = run(cliff.Spec{Commands: cliff.Commands{"leaf": {}}}, "leaf")
+	is.Equal(err.Error(), "command leaf is not initialized")
+
+	var n int
+	err = run(cliff.Spec{
+		Flags:    cliff.Flags{"num": cliff.F(&n, 'n', 0, "")},
+		Args:     cliff.Args{},
+		Commands: cliff.Commands{"leaf": leaf},
+	}, "leaf")
+	is.Equal(err.Error(), "positional arguments cannot be used together with subcommands")
+
+	err = run(cliff.Spec{
+		Flags:    cliff.Flags{"number": cliff.F(&n, 'n', 0, "").Persistent()},
+		Commands: cliff.Commands{"leaf": leaf},
+	}, "leaf")
+	is.Equal(err.Error(), "shorthand -n of persistent flag --number is already used")
+}
@@ -0,0 +1,370 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ErrCompletion is returned when a shell completion script or completion candidates were printed.
+var ErrCompletion = errors.New("completion requested")
+
+// Shell is a shell for which a completion script can be generated.
+type Shell string
+
+const (
+	Bash       Shell = "bash"
+	Zsh        Shell = "zsh"
+	Fish       Shell = "fish"
+	PowerShell Shell = "powershell"
+)
+
+var shells = map[string]Shell{
+	string(Bash):       Bash,
+	string(Zsh):        Zsh,
+	string(Fish):       Fish,
+	string(PowerShell): PowerShell,
+}
+
+// annotationComplete marks flags with a dynamic completion function.
+const annotationComplete = "cliff-complete"
+
+// completeArg is the hidden first argument making the program
+// print completion candidates for a flag value instead of running.
+const completeArg = "__complete"
+
+var notIdent = regexp.MustCompile(`[^a-zA-Z0-9_]`).ReplaceAllString
+
+// compFlag is all info about a flag needed to complete it.
+type compFlag struct {
+	long       string
+	short      string
+	help       string
+	takesValue bool     // the flag requires a value
+	repeatable bool     // the flag can be passed multiple times
+	choices    []string // all allowed values
+	path       bool     // the value is a file path
+	dynamic    bool     // candidates are provided by the program at runtime
+}
+
+// Completion writes the shell completion script for the program with the given name.
+//
+// Hidden and deprecated flags are not completed.
+//
+// Typical usage:
+//
+//	flags.Completion(os.Stdout, cliff.Bash, "example")
+func (fs Flags) Completion(w io.Writer, shell Shell, name string) error {
+	pfs, err := fs.PFlagSet(io.Discard, name)
+	if err != nil {
+		return err
+	}
+	return writeCompletion(w, shell, name, pfs)
+}
+
+func writeCompletion(w io.Writer, shell Shell, name string, pfs *pflag.FlagSet) error {
+	flags := compFlags(pfs)
+	switch shell {
+	case Bash:
+		writeBash(w, name, flags)
+	case Zsh:
+		writeZsh(w, name, flags)
+	case Fish:
+		writeFish(w, name, flags)
+	case PowerShell:
+		writePowerShell(w, name, flags)
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+	return nil
+}
+
+// compFlags collects info about all visible flags in the flag set.
+func compFlags(pfs *pflag.FlagSet) []compFlag {
+	flags := make([]compFlag, 0)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		// Negations and aliases are hidden only to not be shown in help.
+		owner := ownerOf(pfs, pf)
+		if owner.Hidden || pf.Deprecated != "" {
+			return
+		}
+		// Aliases accept the same values as the flag they belong to.
+		annotations := pf.Annotations
+		if pf.Annotations[annotationAliasOf] != nil {
+			annotations = owner.Annotations
+		}
+		short := pf.Shorthand
+		if pf.ShorthandDeprecated != "" {
+			short = ""
+		}
+		_, help := pflag.UnquoteUsage(pf)
+		typ := pf.Value.Type()
+		flags = append(flags, compFlag{
+			long:       pf.Name,
+			short:      short,
+			help:       help,
+			takesValue: pf.NoOptDefVal == "",
+			repeatable: typ == "count" || isSlice(pf.Value) || strings.HasPrefix(typ, "stringTo"),
+			choices:    annotations[annotationChoices],
+			path:       typ == "path",
+			dynamic:    annotations[annotationComplete] != nil,
+		})
+	})
+	return flags
+}
+
+// names returns all names of the flag with dashes.
+func (f compFlag) names() []string {
+	if f.short == "" {
+		return []string{"--" + f.long}
+	}
+	return []string{"--" + f.long, "-" + f.short}
+}
+
+func writeBash(w io.Writer, name string, flags []compFlag) {
+	fn := "_" + notIdent(name, "_") + "_completion"
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `    local prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	for _, f := range flags {
+		switch {
+		case f.dynamic:
+			fmt.Fprintf(w, "        %s)\n", strings.Join(f.names(), "|"))
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W \"$(\"${COMP_WORDS[0]}\" %s \"$prev\" \"$cur\" 2>/dev/null)\" -- \"$cur\"))\n", completeArg)
+			fmt.Fprintln(w, "            return;;")
+		case len(f.choices) != 0:
+			fmt.Fprintf(w, "        %s)\n", strings.Join(f.names(), "|"))
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shQuote(strings.Join(f.choices, " ")))
+			fmt.Fprintln(w, "            return;;")
+		case f.path:
+			fmt.Fprintf(w, "        %s)\n", strings.Join(f.names(), "|"))
+			fmt.Fprintln(w, `            COMPREPLY=($(compgen -f -- "$cur"))`)
+			fmt.Fprintln(w, "            return;;")
+		case f.takesValue:
+			fmt.Fprintf(w, "        %s)\n", strings.Join(f.names(), "|"))
+			fmt.Fprintln(w, "            return;;")
+		}
+	}
+	fmt.Fprintln(w, "    esac")
+	names := make([]string, 0, len(flags)*2)
+	for _, f := range flags {
+		names = append(names, f.names()...)
+	}
+	fmt.Fprintln(w, `    if [[ "$cur" == -* ]]; then`)
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shQuote(strings.Join(names, " ")))
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "complete -o default -F %s %s\n", fn, shQuote(name))
+}
+
+func writeZsh(w io.Writer, name string, flags []compFlag) {
+	fn := "_" + notIdent(name, "_")
+	fmt.Fprintf(w, "#compdef %s\n\n", name)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, "    _arguments -s \\")
+	for _, f := range flags {
+		spec := ""
+		if f.repeatable {
+			spec += shQuote("*")
+		} else if f.short != "" {
+			spec += shQuote(fmt.Sprintf("(-%s --%s)", f.short, f.long))
+		}
+		if f.short != "" {
+			spec += fmt.Sprintf("{-%s,--%s}", f.short, f.long)
+		} else {
+			spec += "--" + f.long
+		}
+		action := "[" + zshEscape(f.help) + "]"
+		if f.takesValue {
+			action += ":" + zshEscape(f.long) + ":"
+			switch {
+			case f.dynamic:
+				action += fmt.Sprintf(`{compadd -- ${(f)"$(${words[1]} %s --%s "$PREFIX" 2>/dev/null)"}}`, completeArg, f.long)
+			case len(f.choices) != 0:
+				action += "(" + strings.Join(f.choices, " ") + ")"
+			case f.path:
+				action += "_files"
+			}
+		}
+		fmt.Fprintf(w, "        %s%s \\\n", spec, shQuote(action))
+	}
+	fmt.Fprintln(w, "        '*:argument:_files'")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "\ncompdef %s %s\n", fn, name)
+}
+
+func writeFish(w io.Writer, name string, flags []compFlag) {
+	for _, f := range flags {
+		line := "complete -c " + fishQuote(name) + " -l " + f.long
+		if f.short != "" {
+			line += " -s " + f.short
+		}
+		if f.help != "" {
+			line += " -d " + fishQuote(f.help)
+		}
+		if f.takesValue {
+			switch {
+			case f.dynamic:
+				call := fmt.Sprintf("(%s %s --%s (commandline -ct))", fishQuote(name), completeArg, f.long)
+				line += " -x -a " + fishQuote(call)
+			case len(f.choices) != 0:
+				line += " -x -a " + fishQuote(strings.Join(f.choices, " "))
+			case f.path:
+				line += " -r -F"
+			default:
+				line += " -r"
+			}
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func writePowerShell(w io.Writer, name string, flags []compFlag) {
+	fmt.Fprintf(w, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", psQuote(name))
+	fmt.Fprintln(w, "    param($wordToComplete, $commandAst, $cursorPosition)")
+	fmt.Fprintln(w, "    $words = @($commandAst.CommandElements | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })")
+	fmt.Fprintln(w, "    $prev = if ($words.Count -gt 0) { $words[-1] } else { '' }")
+	fmt.Fprintln(w, "    $choices = @{")
+	for _, f := range flags {
+		if len(f.choices) == 0 {
+			continue
+		}
+		quoted := make([]string, len(f.choices))
+		for i, c := range f.choices {
+			quoted[i] = psQuote(c)
+		}
+		for _, n := range f.names() {
+			fmt.Fprintf(w, "        %s = @(%s)\n", psQuote(n), strings.Join(quoted, ", "))
+		}
+	}
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "    $dynamic = @(")
+	for _, f := range flags {
+		if f.dynamic {
+			for _, n := range f.names() {
+				fmt.Fprintf(w, "        %s,\n", psQuote(n))
+			}
+		}
+	}
+	fmt.Fprintln(w, "        $null")
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w, "    if ($prev -and $dynamic -contains $prev) {")
+	fmt.Fprintf(w, "        & %s %s $prev $wordToComplete 2>$null | ForEach-Object {\n", psQuote(name), completeArg)
+	fmt.Fprintln(w, "            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)")
+	fmt.Fprintln(w, "        }")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "    if ($choices.ContainsKey($prev)) {")
+	fmt.Fprintln(w, "        $choices[$prev] | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {")
+	fmt.Fprintln(w, "            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)")
+	fmt.Fprintln(w, "        }")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "    if ($wordToComplete -like '-*') {")
+	fmt.Fprintln(w, "        $flags = @(")
+	for _, f := range flags {
+		help := f.help
+		if help == "" {
+			help = f.long
+		}
+		for _, n := range f.names() {
+			fmt.Fprintf(w, "            @(%s, %s),\n", psQuote(n), psQuote(help))
+		}
+	}
+	fmt.Fprintln(w, "            $null")
+	fmt.Fprintln(w, "        )")
+	fmt.Fprintln(w, "        $flags | Where-Object { $_ -and $_[0] -like \"$wordToComplete*\" } | ForEach-Object {")
+	fmt.Fprintln(w, "            [System.Management.Automation.CompletionResult]::new($_[0], $_[0], 'ParameterName', $_[1])")
+	fmt.Fprintln(w, "        }")
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "}")
+}
+
+// shQuote quotes the string for bash and zsh.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshEscape escapes characters that have special meaning in zsh _arguments specs.
+func zshEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+	return r.Replace(s)
+}
+
+// fishQuote quotes the string for fish.
+func fishQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+	return "'" + r.Replace(s) + "'"
+}
+
+// psQuote quotes the string for PowerShell.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// addCompletionFlag adds the built-in flag printing the completion script.
+//
+// The chosen shell will be written into the given target.
+func addCompletionFlag(pfs *pflag.FlagSet, name string, shell *Shell) error {
+	err := validateName(name)
+	if err != nil {
+		return fmt.Errorf("validate flag name (%s): %v", name, err)
+	}
+	if pfs.Lookup(name) != nil {
+		return fmt.Errorf("completion flag %s is already defined", name)
+	}
+	flag := Enum(shell, 0, "", shells, "print shell completion script")
+	return flag.AddTo(pfs, name)
+}
+
+// hasCompleters checks if any of the flags has a dynamic completion function.
+func (fs Flags) hasCompleters() bool {
+	for _, flag := range fs {
+		if flag.complete != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// complete prints completion candidates for the flag value.
+//
+// The args are the flag name with dashes and the already typed prefix of the value.
+func (fs Flags) complete(w io.Writer, pfs *pflag.FlagSet, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("%s expects a flag and a prefix", completeArg)
+	}
+	var pf *pflag.Flag
+	switch {
+	case strings.HasPrefix(args[0], "--"):
+		pf = pfs.Lookup(args[0][2:])
+	case strings.HasPrefix(args[0], "-") && len(args[0]) == 2:
+		pf = pfs.ShorthandLookup(args[0][1:])
+	}
+	if pf == nil {
+		return fmt.Errorf("unknown flag: %s", args[0])
+	}
+	prefix := ""
+	if len(args) == 2 {
+		prefix = args[1]
+	}
+	flag, found := fs[ownerOf(pfs, pf).Name]
+	if !found || flag.complete == nil {
+		return nil
+	}
+	for _, c := range flag.complete(prefix) {
+		fmt.Fprintln(w, c)
+	}
+	return nil
+}
+
+// programName returns the program name for completion scripts.
+func programName(arg0 string) string {
+	return filepath.Base(arg0)
+}
@@ -0,0 +1,178 @@
+package cliff_test
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func completionFlags() cliff.Flags {
+	var format, host, old string
+	var config cliff.Path
+	var verbose cliff.Count
+	var tags []string
+	var profile string
+	profiles := func(prefix string) []string {
+		return []string{prefix + "dev", prefix + "prod"}
+	}
+	formats := map[string]string{"json": "json", "text": "text"}
+	return cliff.Flags{
+		"format":  cliff.Enum(&format, 'f', "text", formats, "output format"),
+		"config":  cliff.F(&config, 'c', "", "path to the config [file]"),
+		"host":    cliff.F(&host, 'h', "", "host to serve on").ShortDeprecated("use --host"),
+		"old":     cliff.F(&old, 0, "", "").Deprecated("don't use it"),
+		"secret":  cliff.F(&old, 0, "", "").Hidden(),
+		"verbose": cliff.F(&verbose, 'v', 0, "it's verbosity"),
+		"tag":     cliff.F(&tags, 't', nil, ""),
+		"profile": cliff.F(&profile, 'p', "", "").Complete(profiles),
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	is := is.New(t)
+
+	for _, shell := range []cliff.Shell{cliff.Bash, cliff.Zsh, cliff.Fish, cliff.PowerShell} {
+		var buf bytes.Buffer
+		err := completionFlags().Completion(&buf, shell, "my-app")
+		is.NoErr(err)
+		out := buf.String()
+		is.True(strings.Contains(out, "format"))
+		is.True(strings.Contains(out, "json"))
+		is.True(!strings.Contains(out, "secret"))
+		is.True(!strings.Contains(out, "old"))
+		is.True(strings.Contains(out, "__complete"))
+	}
+
+	var buf bytes.Buffer
+	err := completionFlags().Completion(&buf, "tcsh", "my-app")
+	is.Equal(err.Error(), "unsupported shell: tcsh")
+}
+
+func TestCompletion_Zsh(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := completionFlags().Completion(&buf, cliff.Zsh, "my-app")
+	is.NoErr(err)
+	expected := `#compdef my-app
+
+_my_app() {
+    _arguments -s \
+        '(-c --config)'{-c,--config}'[path to the config \[file\]]:config:_files' \
+        '(-f --format)'{-f,--format}'[output format]:format:(json text)' \
+        --host'[host to serve on]:host:' \
+        '(-p --profile)'{-p,--profile}'[]:profile:{compadd -- ${(f)"$(${words[1]} __complete --profile "$PREFIX" 2>/dev/null)"}}' \
+        '*'{-t,--tag}'[]:tag:' \
+        '*'{-v,--verbose}'[it'\''s verbosity]' \
+        '*:argument:_files'
+}
+
+compdef _my_app my-app
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestCompletion_BashSyntax(t *testing.T) {
+	is := is.New(t)
+
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	var buf bytes.Buffer
+	err = completionFlags().Completion(&buf, cliff.Bash, "my-app")
+	is.NoErr(err)
+	cmd := exec.Command(bash, "-n")
+	cmd.Stdin = &buf
+	out, err := cmd.CombinedOutput()
+	is.Equal(string(out), "")
+	is.NoErr(err)
+}
+
+func TestCompletionFlag_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var debug bool
+	spec := cliff.Spec{
+		Flags:          cliff.Flags{"debug": cliff.F(&debug, 0, false, "")},
+		CompletionFlag: "debug",
+	}
+	err := spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "completion flag debug is already defined")
+
+	spec.CompletionFlag = "completion"
+	err = spec.Parse(io.Discard, []string{"example", "--completion", "tcsh"})
+	is.Equal(err.Error(), `invalid argument "tcsh" for "--completion" flag: must be one of: bash, fish, powershell, zsh; did you mean bash or fish or zsh?`)
+}
+
+func TestCompletion_Dynamic(t *testing.T) {
+	is := is.New(t)
+
+	var profile, host string
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"profile": cliff.F(&profile, 'p', "", "").Complete(func(prefix string) []string {
+				return []string{prefix + "1", prefix + "2"}
+			}).Alias("prof"),
+			"host": cliff.F(&host, 0, "", ""),
+		},
+	}
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		spec.Stdout = &buf
+		err := spec.Parse(io.Discard, append([]string{"example", "__complete"}, args...))
+		return buf.String(), err
+	}
+
+	out, err := run("--profile", "dev")
+	is.Equal(err, cliff.ErrCompletion)
+	is.Equal(out, "dev1\ndev2\n")
+
+	out, err = run("-p")
+	is.Equal(err, cliff.ErrCompletion)
+	is.Equal(out, "1\n2\n")
+
+	out, err = run("--prof", "")
+	is.Equal(err, cliff.ErrCompletion)
+	is.Equal(out, "1\n2\n")
+
+	out, err = run("--host", "a")
+	is.Equal(err, cliff.ErrCompletion)
+	is.Equal(out, "")
+
+	_, err = run("--port", "a")
+	is.Equal(err.Error(), "unknown flag: --port")
+
+	_, err = run()
+	is.Equal(err.Error(), "__complete expects a flag and a prefix")
+
+	// Without completion functions, the argument isn't special.
+	spec.Flags = cliff.Flags{"host": cliff.F(&host, 0, "", "")}
+	_, err = run("--host", "a")
+	is.NoErr(err)
+}
+
+func TestCompletion_Alias(t *testing.T) {
+	is := is.New(t)
+
+	var format, profile string
+	formats := map[string]string{"json": "json", "text": "text"}
+	profiles := func(prefix string) []string { return nil }
+	flags := cliff.Flags{
+		"format":  cliff.Enum(&format, 0, "text", formats, "").Alias("fmt"),
+		"profile": cliff.F(&profile, 0, "", "").Complete(profiles).Alias("prof"),
+	}
+	var buf bytes.Buffer
+	err := flags.Completion(&buf, cliff.Bash, "my-app")
+	is.NoErr(err)
+	out := buf.String()
+	is.True(strings.Contains(out, "--fmt)\n"))
+	is.True(!strings.Contains(out, "--fmt) return;;"))
+	is.Equal(strings.Count(out, "json text"), 2)
+	is.Equal(strings.Count(out, "__complete"), 2)
+}
@@ -0,0 +1,320 @@
+package cliff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ConfigFormat parses the content of a config file into a list of entries.
+type ConfigFormat func(content []byte) ([]ConfigEntry, error)
+
+// ConfigEntry is a single value for a flag read from a config file.
+type ConfigEntry struct {
+	Key   string // the flag name
+	Value string // the raw value, as it would be passed in CLI
+	Line  int    // the line number in the file, starting from 1
+}
+
+// LineError is an error in a specific line of a config file.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// JSON parses config files in JSON format.
+//
+// The file must contain a single object mapping flag names to values.
+// Arrays are treated as the flag passed multiple times, once for each item.
+// Objects are treated as maps, each key-value pair is passed as "key=value".
+func JSON(content []byte) ([]ConfigEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	lineAt := func() int {
+		return bytes.Count(content[:dec.InputOffset()], []byte("\n")) + 1
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, jsonError(content, err)
+	}
+	if tok != json.Delim('{') {
+		return nil, LineError{Line: lineAt(), Err: errors.New("expected object")}
+	}
+	entries := make([]ConfigEntry, 0)
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, jsonError(content, err)
+		}
+		key := tok.(string)
+		line := lineAt()
+		vals, err := jsonValues(dec)
+		if err != nil {
+			var lineErr LineError
+			if errors.As(err, &lineErr) {
+				return nil, err
+			}
+			return nil, LineError{Line: line, Err: err}
+		}
+		for _, val := range vals {
+			entries = append(entries, ConfigEntry{Key: key, Value: val, Line: line})
+		}
+	}
+	return entries, nil
+}
+
+// jsonValues reads the next JSON value and converts it into raw CLI values.
+func jsonValues(dec *json.Decoder) ([]string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('['):
+		vals := make([]string, 0)
+		for dec.More() {
+			tok, err = dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := jsonScalar(tok)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, val)
+		}
+		_, err = dec.Token()
+		return vals, err
+	case json.Delim('{'):
+		vals := make([]string, 0)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			tok, err = dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := jsonScalar(tok)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, fmt.Sprintf("%s=%s", key, val))
+		}
+		_, err = dec.Token()
+		return vals, err
+	}
+	val, err := jsonScalar(tok)
+	if err != nil {
+		return nil, err
+	}
+	return []string{val}, nil
+}
+
+// jsonScalar converts a JSON string, number, or boolean into a raw CLI value.
+func jsonScalar(tok json.Token) (string, error) {
+	switch val := tok.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case nil:
+		return "", errors.New("null is not supported")
+	}
+	return "", errors.New("nested arrays and objects are not supported")
+}
+
+// jsonError adds the line number to JSON syntax errors.
+func jsonError(content []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := bytes.Count(content[:syntaxErr.Offset], []byte("\n")) + 1
+		return LineError{Line: line, Err: err}
+	}
+	return err
+}
+
+// DotEnv parses config files in dotenv format.
+//
+// Each line is "KEY=value", optionally prefixed by "export".
+// Keys are converted to flag names, so "DRY_RUN" becomes "dry-run".
+// Values can be quoted. Lines starting with "#" are comments.
+func DotEnv(content []byte) ([]ConfigEntry, error) {
+	entries := make([]ConfigEntry, 0)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, val, err := parseKeyValue(line)
+		if err != nil {
+			return nil, LineError{Line: i + 1, Err: err}
+		}
+		key = strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+		entries = append(entries, ConfigEntry{Key: key, Value: val, Line: i + 1})
+	}
+	return entries, nil
+}
+
+// INI parses config files in INI-like format.
+//
+// Each line is "key = value". Values can be quoted.
+// Lines starting with "#" or ";" are comments.
+// Keys inside of a "[section]" are prefixed by the section name and a dash,
+// so "port" in section "[server]" becomes "server-port".
+func INI(content []byte) ([]ConfigEntry, error) {
+	entries := make([]ConfigEntry, 0)
+	section := ""
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, LineError{Line: i + 1, Err: errors.New("unclosed section")}
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, val, err := parseKeyValue(line)
+		if err != nil {
+			return nil, LineError{Line: i + 1, Err: err}
+		}
+		if section != "" {
+			key = section + "-" + key
+		}
+		entries = append(entries, ConfigEntry{Key: key, Value: val, Line: i + 1})
+	}
+	return entries, nil
+}
+
+// parseKeyValue parses "key=value" line with optionally quoted value.
+func parseKeyValue(line string) (string, string, error) {
+	key, val, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", errors.New("expected key=value")
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", "", errors.New("empty key")
+	}
+	val = strings.TrimSpace(val)
+	if len(val) >= 2 {
+		switch {
+		case val[0] == '"' && val[len(val)-1] == '"':
+			unquoted, err := strconv.Unquote(val)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid quoted value: %v", err)
+			}
+			val = unquoted
+		case val[0] == '\'' && val[len(val)-1] == '\'':
+			val = val[1 : len(val)-1]
+		}
+	}
+	return key, val, nil
+}
+
+// detectConfigFormat picks the config format based on the file extension.
+func detectConfigFormat(path string) (ConfigFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, nil
+	case ".env":
+		return DotEnv, nil
+	case ".ini", ".cfg", ".conf":
+		return INI, nil
+	}
+	return nil, fmt.Errorf("cannot detect config format for %s", path)
+}
+
+// applyConfig reads the config file and sets values for the flags that were not set yet.
+//
+// The set contains flags that are already set from env vars.
+// Flags updated from the config file are added into it.
+// If apply is not nil, only the flags for which it returns true are updated.
+func (s Spec) applyConfig(pfs *pflag.FlagSet, set map[*pflag.Flag]Source, apply func(*pflag.Flag) bool) error {
+	cf := pfs.Lookup(s.ConfigFlag)
+	if cf == nil {
+		return DefinitionError{Err: fmt.Errorf("config flag not found: %s", s.ConfigFlag)}
+	}
+	if cf.Value.Type() != "string" && cf.Value.Type() != "path" {
+		return DefinitionError{Err: fmt.Errorf("config flag must be a string: %s", s.ConfigFlag)}
+	}
+	path := cf.Value.String()
+	if path == "" {
+		return nil
+	}
+	format := s.ConfigFormat
+	if format == nil {
+		var err error
+		format, err = detectConfigFormat(path)
+		if err != nil {
+			return err
+		}
+	}
+	readFile := s.ReadFile
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+	content, err := readFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %v", err)
+	}
+	entries, err := format(content)
+	if err != nil {
+		var lineErr LineError
+		if errors.As(err, &lineErr) {
+			return fmt.Errorf("%s:%d: %v", path, lineErr.Line, lineErr.Err)
+		}
+		return fmt.Errorf("parse config %s: %v", path, err)
+	}
+	fromConfig := make(map[*pflag.Flag]bool)
+	for _, e := range entries {
+		pf := pfs.Lookup(e.Key)
+		if pf == nil {
+			return fmt.Errorf("%s:%d: %w", path, e.Line, UnknownFlagError{Name: e.Key})
+		}
+		// Aliases and negations are set only if the flag they belong to is not set.
+		owner := ownerOf(pfs, pf)
+		if flagSource(owner, set) != SourceDefault || owner == cf {
+			continue
+		}
+		if apply != nil && !apply(owner) {
+			continue
+		}
+		err = pf.Value.Set(e.Value)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, e.Line, InvalidValueError{
+				Name:  pf.Name,
+				Value: e.Value,
+				Err:   err,
+				msg:   fmt.Sprintf("invalid argument %q for %q flag: %v", e.Value, flagName(pf), err),
+			})
+		}
+		fromConfig[owner] = true
+	}
+	for pf := range fromConfig {
+		set[pf] = SourceConfig
+	}
+	return nil
+}
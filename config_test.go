@@ -0,0 +1,164 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+type fileConfig struct {
+	config  string
+	host    string
+	port    int
+	tags    []string
+	labels  map[string]string
+	data    cliff.BytesHex
+	verbose cliff.Count
+}
+
+func parseFileConfig(files map[string]string, env map[string]string, args ...string) (fileConfig, error) {
+	var c fileConfig
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"config":  cliff.F(&c.config, 'c', "", "path to the config file"),
+			"host":    cliff.F(&c.host, 0, "127.0.0.1", ""),
+			"port":    cliff.F(&c.port, 'p', 8080, "").Env("PORT"),
+			"tags":    cliff.F(&c.tags, 0, nil, ""),
+			"labels":  cliff.F(&c.labels, 0, nil, ""),
+			"data":    cliff.F(&c.data, 0, nil, ""),
+			"verbose": cliff.F(&c.verbose, 'v', 0, ""),
+		},
+		ConfigFlag: "config",
+		LookupEnv: func(key string) (string, bool) {
+			val, found := env[key]
+			return val, found
+		},
+		ReadFile: func(path string) ([]byte, error) {
+			return []byte(files[path]), nil
+		},
+	}
+	err := spec.Parse(io.Discard, append([]string{"example"}, args...))
+	return c, err
+}
+
+func TestConfig_JSON(t *testing.T) {
+	is := is.New(t)
+	files := map[string]string{
+		"app.json": `{
+			"host": "localhost",
+			"port": 80,
+			"tags": ["a", "b,c"],
+			"labels": {"env": "prod"},
+			"data": "4F",
+			"verbose": 3
+		}`,
+	}
+	c, err := parseFileConfig(files, nil, "-c", "app.json")
+	is.NoErr(err)
+	is.Equal(c.host, "localhost")
+	is.Equal(c.port, 80)
+	is.Equal(c.tags, []string{"a", "b", "c"})
+	is.Equal(c.labels, map[string]string{"env": "prod"})
+	is.Equal(c.data, cliff.BytesHex{79})
+	is.Equal(c.verbose, cliff.Count(3))
+}
+
+func TestConfig_Precedence(t *testing.T) {
+	is := is.New(t)
+	files := map[string]string{
+		"app.env": "HOST=localhost\nexport PORT=80\nTAGS='a'\nTAGS=\"b\"\n",
+	}
+	c, err := parseFileConfig(files, nil, "-c", "app.env")
+	is.NoErr(err)
+	is.Equal(c.host, "localhost")
+	is.Equal(c.port, 80)
+	is.Equal(c.tags, []string{"a", "b"})
+
+	c, err = parseFileConfig(files, map[string]string{"PORT": "81"}, "-c", "app.env", "--host", "example.com")
+	is.NoErr(err)
+	is.Equal(c.host, "example.com")
+	is.Equal(c.port, 81)
+
+	c, err = parseFileConfig(files, nil)
+	is.NoErr(err)
+	is.Equal(c.host, "127.0.0.1")
+}
+
+func TestConfig_INI(t *testing.T) {
+	is := is.New(t)
+	files := map[string]string{
+		"app.ini": "; comment\nhost = localhost\n[labels]\n",
+		"bad.ini": "host = localhost\n\nhots = localhost\n",
+		"bad.cfg": "host localhost",
+	}
+	c, err := parseFileConfig(files, nil, "-c", "app.ini")
+	is.NoErr(err)
+	is.Equal(c.host, "localhost")
+
+	_, err = parseFileConfig(files, nil, "-c", "bad.ini")
+	is.Equal(err.Error(), "bad.ini:3: unknown flag: hots")
+
+	_, err = parseFileConfig(files, nil, "-c", "bad.cfg")
+	is.Equal(err.Error(), "bad.cfg:1: expected key=value")
+}
+
+func TestConfig_Errors(t *testing.T) {
+	is := is.New(t)
+	files := map[string]string{
+		"a.json": "{\n\"port\": \"eighty\"}",
+		"b.json": "{\n\"port\": 80,\n\"host\": [[]]}",
+		"c.json": "{\n\"port\": 80,,}",
+		"d.json": "[]",
+	}
+	_, err := parseFileConfig(files, nil, "-c", "a.json")
+	is.Equal(err.Error(), `a.json:2: invalid argument "eighty" for "-p, --port" flag: strconv.ParseInt: parsing "eighty": invalid syntax`)
+
+	_, err = parseFileConfig(files, nil, "-c", "b.json")
+	is.Equal(err.Error(), "b.json:3: nested arrays and objects are not supported")
+
+	_, err = parseFileConfig(files, nil, "-c", "c.json")
+	is.Equal(err.Error(), "c.json:2: invalid character ',' looking for beginning of value")
+
+	_, err = parseFileConfig(files, nil, "-c", "d.json")
+	is.Equal(err.Error(), "d.json:1: expected object")
+
+	_, err = parseFileConfig(files, nil, "-c", "app.yaml")
+	is.Equal(err.Error(), "cannot detect config format for app.yaml")
+}
+
+func TestConfig_Subcommand(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		config string
+		tags   []string
+	}
+	run := func(args ...string) Config {
+		var c Config
+		serve := cliff.Cmd(func(*struct{}) cliff.Flags {
+			return cliff.Flags{}
+		}, func(struct{}) error { return nil }, "")
+		root := cliff.Cmd(func(*struct{}) cliff.Spec {
+			return cliff.Spec{
+				Flags: cliff.Flags{
+					"config": cliff.F(&c.config, 'c', "app.json", "").Persistent(),
+					"tags":   cliff.F(&c.tags, 0, nil, "").Persistent(),
+				},
+				Commands:   cliff.Commands{"serve": serve},
+				ConfigFlag: "config",
+				ReadFile: func(path string) ([]byte, error) {
+					return []byte(`{"tags": ["a", "b"]}`), nil
+				},
+			}
+		}, nil, "")
+		err := cliff.Run(io.Discard, append([]string{"example"}, args...), root)
+		is.NoErr(err)
+		return c
+	}
+
+	is.Equal(run("serve", "--tags", "c").tags, []string{"c"})
+	is.Equal(run("--tags", "c", "serve").tags, []string{"c"})
+	is.Equal(run("serve").tags, []string{"a", "b"})
+}
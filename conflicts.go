@@ -0,0 +1,95 @@
+package cliff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// CheckConflicts checks that the flags don't conflict with each other
+// or with flags of the given parent flag set.
+//
+// Long names, aliases, negations, and shorthands are checked.
+// All conflicts are reported in a single [DefinitionError].
+// The parent may be nil.
+//
+// Flags are not added anywhere, so values of already parsed flags are not changed.
+//
+// Use it before merging the flag set returned by [Flags.PFlagSet]
+// into the parent using [pflag.FlagSet.AddFlagSet], which panics on conflicting shorthands
+// and silently skips flags with already defined names.
+func (fs Flags) CheckConflicts(parent *pflag.FlagSet) error {
+	longs := make(map[string]string)
+	shorts := make(map[string]string)
+	conflicts := make([]string, 0)
+	if parent != nil {
+		parent.VisitAll(func(pf *pflag.Flag) {
+			longs[pf.Name] = "parent flag --" + pf.Name
+			if pf.Shorthand != "" {
+				shorts[pf.Shorthand] = "parent flag --" + pf.Name
+			}
+		})
+	}
+	for _, name := range fs.names() {
+		// Flags that cannot be added are reported when adding them for real.
+		f := fs[name]
+		if f.setter == nil {
+			continue
+		}
+		for _, long := range f.longNames(name) {
+			other, found := longs[long.name]
+			if found {
+				conflicts = append(conflicts, fmt.Sprintf("--%s is defined by %s and %s", long.name, other, long.owner))
+			} else {
+				longs[long.name] = long.owner
+			}
+		}
+		short := f.setter.shorthand()
+		if short == "" || !isAlNum(short) {
+			continue
+		}
+		owner := "flag --" + name
+		other, found := shorts[short]
+		if found {
+			conflicts = append(conflicts, fmt.Sprintf("-%s is used by %s and %s", short, other, owner))
+		} else {
+			shorts[short] = owner
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return DefinitionError{Err: fmt.Errorf("conflicting flags: %s", strings.Join(conflicts, "; "))}
+}
+
+// definedName is a long name defined by a flag, its alias, or its negation.
+type definedName struct {
+	name  string
+	owner string // description of what defines the name, like "alias of --listen"
+}
+
+// longNames returns all long names defined by the flag with the given name, sorted.
+func (f Flag) longNames(name string) []definedName {
+	names := []definedName{{name: name, owner: "flag --" + name}}
+	for _, alias := range f.aliases {
+		names = append(names, definedName{name: alias, owner: "alias of --" + name})
+	}
+	if f.negatable {
+		names = append(names, definedName{name: "no-" + name, owner: "negation of --" + name})
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].name < names[j].name })
+	return names
+}
+
+// flagOwner describes the flag defining the given name, like "flag --addr" or "alias of --listen".
+func flagOwner(pf *pflag.Flag) string {
+	if owner := pf.Annotations[annotationAliasOf]; owner != nil {
+		return "alias of --" + owner[0]
+	}
+	if owner := pf.Annotations[annotationNegates]; owner != nil {
+		return "negation of --" + owner[0]
+	}
+	return "flag --" + pf.Name
+}
@@ -0,0 +1,99 @@
+package cliff_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+	"github.com/spf13/pflag"
+)
+
+func TestFlags_PFlagSet_Conflicts(t *testing.T) {
+	is := is.New(t)
+
+	var port, profile, verbose, version int
+	var listen, addr string
+	flags := cliff.Flags{
+		"port":    cliff.F(&port, 'p', 0, ""),
+		"profile": cliff.F(&profile, 'p', 0, ""),
+		"verbose": cliff.F(&verbose, 'v', 0, ""),
+		"version": cliff.F(&version, 'v', 0, ""),
+		"listen":  cliff.F(&listen, 0, "", "").Alias("addr"),
+		"addr":    cliff.F(&addr, 0, "", ""),
+	}
+	want := "conflicting flags: " +
+		"--addr is defined by flag --addr and alias of --listen; " +
+		"-p is used by flag --port and flag --profile; " +
+		"-v is used by flag --verbose and flag --version"
+	// The order of flags in the map is random, the error must not be.
+	for i := 0; i < 10; i++ {
+		_, err := flags.PFlagSet(io.Discard, "example")
+		is.True(err != nil)
+		is.Equal(err.Error(), want)
+		var defErr cliff.DefinitionError
+		is.True(errors.As(err, &defErr))
+	}
+
+	err := flags.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), want)
+}
+
+func TestFlags_CheckConflicts(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var host string
+	var debug bool
+	parent := pflag.NewFlagSet("parent", pflag.ContinueOnError)
+	parent.StringP("host", "H", "", "")
+	parent.BoolP("dry-run", "d", false, "")
+
+	flags := cliff.Flags{
+		"port":  cliff.F(&port, 'p', 0, ""),
+		"host":  cliff.F(&host, 0, "", ""),
+		"debug": cliff.F(&debug, 'd', false, "").Negatable(),
+	}
+	err := flags.CheckConflicts(parent)
+	is.Equal(err.Error(), "conflicting flags: "+
+		"-d is used by parent flag --dry-run and flag --debug; "+
+		"--host is defined by parent flag --host and flag --host")
+
+	flags = cliff.Flags{"port": cliff.F(&port, 'p', 0, "")}
+	is.NoErr(flags.CheckConflicts(nil))
+	is.NoErr(flags.CheckConflicts(parent))
+	pfs, err := flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	parent.AddFlagSet(pfs)
+	is.True(parent.Lookup("port") != nil)
+
+	// Checking conflicts after parsing doesn't reset the parsed values.
+	is.NoErr(parent.Parse([]string{"--port", "80"}))
+	is.NoErr(flags.CheckConflicts(nil))
+	is.Equal(port, 80)
+}
+
+func TestCmd_InheritConflicts(t *testing.T) {
+	is := is.New(t)
+
+	var a, b, c, d int
+	leaf := cliff.Cmd(func(*struct{}) cliff.Flags {
+		return cliff.Flags{
+			"c": cliff.F(&c, 'a', 0, ""),
+			"d": cliff.F(&d, 'b', 0, ""),
+		}
+	}, func(struct{}) error { return nil }, "")
+	root := cliff.Cmd(func(*struct{}) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"a": cliff.F(&a, 'a', 0, "").Persistent(),
+				"b": cliff.F(&b, 'b', 0, "").Persistent(),
+			},
+			Commands: cliff.Commands{"leaf": leaf},
+		}
+	}, func(struct{}) error { return nil }, "")
+	err := cliff.Run(io.Discard, []string{"example", "leaf"}, root)
+	is.Equal(err.Error(), "shorthand -a of persistent flag --a is already used; "+
+		"shorthand -b of persistent flag --b is already used")
+}
@@ -0,0 +1,214 @@
+package cliff
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// docCommand is the reference info about a command and its flags.
+type docCommand struct {
+	path  string // the program name followed by subcommand names
+	help  string
+	flags []docFlag
+}
+
+// docFlag is the reference info about a flag.
+type docFlag struct {
+	name   string
+	short  string
+	typ    string
+	def    string
+	help   string
+	depr   string
+	hidden bool
+}
+
+// Markdown writes the reference documentation for all flags in Markdown format.
+//
+// Each command, including all subcommands, gets a table describing its flags.
+// Hidden and deprecated flags are included and marked as such.
+// The output is deterministic, so it can be compared with a committed file in tests.
+//
+// Typical usage:
+//
+//	cliff.Markdown(os.Stdout, "example", initFlags)
+func Markdown[T any, D Definition](w io.Writer, name string, init func(c *T) D) error {
+	cmds, err := collectDocs(name, "", specOf(init))
+	if err != nil {
+		return err
+	}
+	writeMarkdown(w, cmds)
+	return nil
+}
+
+// HTML is like [Markdown] but writes a self-contained HTML page.
+//
+// Typical usage:
+//
+//	cliff.HTML(os.Stdout, "example", initFlags)
+func HTML[T any, D Definition](w io.Writer, name string, init func(c *T) D) error {
+	cmds, err := collectDocs(name, "", specOf(init))
+	if err != nil {
+		return err
+	}
+	writeHTML(w, name, cmds)
+	return nil
+}
+
+// specOf returns the definition produced by the init function.
+func specOf[T any, D Definition](init func(c *T) D) Spec {
+	var config T
+	return toSpec(init(&config))
+}
+
+// collectDocs collects info about the flags of the command and all its subcommands.
+func collectDocs(path, help string, s Spec) ([]docCommand, error) {
+	pfs, err := s.Flags.PFlagSet(io.Discard, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	cmd := docCommand{path: path, help: help}
+	for _, name := range s.Flags.names() {
+		pf := pfs.Lookup(name)
+		typ := ""
+		if pf.Value.Type() != "bool" {
+			typ = pf.Value.Type()
+		}
+		def := pf.DefValue
+		if isZeroFlagDefault(pf) {
+			def = ""
+		}
+		cmd.flags = append(cmd.flags, docFlag{
+			name:   strings.TrimPrefix(longName(pf), "--"),
+			short:  pf.Shorthand,
+			typ:    typ,
+			def:    def,
+			help:   pf.Usage,
+			depr:   pf.Deprecated,
+			hidden: s.Flags[name].hidden,
+		})
+	}
+	cmds := []docCommand{cmd}
+	err = s.Commands.validate()
+	if err != nil {
+		return nil, defError(err)
+	}
+	for _, name := range s.Commands.names() {
+		sub := s.Commands[name]
+		subCmds, err := collectDocs(path+" "+name, sub.help, sub.runner.spec())
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, subCmds...)
+	}
+	return cmds, nil
+}
+
+func writeMarkdown(w io.Writer, cmds []docCommand) {
+	for i, cmd := range cmds {
+		if i == 0 {
+			fmt.Fprintf(w, "# %s\n", mdEscape(cmd.path))
+		} else {
+			fmt.Fprintf(w, "\n## %s\n", mdEscape(cmd.path))
+		}
+		if cmd.help != "" {
+			fmt.Fprintf(w, "\n%s\n", mdEscape(cmd.help))
+		}
+		if len(cmd.flags) == 0 {
+			fmt.Fprintln(w, "\nNo flags.")
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Flag | Shorthand | Type | Default | Description | Deprecated | Hidden |")
+		fmt.Fprintln(w, "| ---- | --------- | ---- | ------- | ----------- | ---------- | ------ |")
+		for _, f := range cmd.flags {
+			short := ""
+			if f.short != "" {
+				short = mdCode("-" + f.short)
+			}
+			def := ""
+			if f.def != "" {
+				def = mdCode(f.def)
+			}
+			fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				mdCode("--"+f.name), short, mdEscape(f.typ), def,
+				mdEscape(f.help), mdEscape(f.depr), yesNo(f.hidden),
+			)
+		}
+	}
+}
+
+func writeHTML(w io.Writer, title string, cmds []docCommand) {
+	esc := html.EscapeString
+	fmt.Fprintln(w, "<!DOCTYPE html>")
+	fmt.Fprintln(w, `<html lang="en">`)
+	fmt.Fprintln(w, "<head>")
+	fmt.Fprintln(w, `<meta charset="utf-8">`)
+	fmt.Fprintf(w, "<title>%s</title>\n", esc(title))
+	fmt.Fprintln(w, "<style>")
+	fmt.Fprintln(w, "body { font-family: sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; }")
+	fmt.Fprintln(w, "table { border-collapse: collapse; width: 100%; }")
+	fmt.Fprintln(w, "th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }")
+	fmt.Fprintln(w, "th { background: #f3f3f3; }")
+	fmt.Fprintln(w, "</style>")
+	fmt.Fprintln(w, "</head>")
+	fmt.Fprintln(w, "<body>")
+	for i, cmd := range cmds {
+		if i == 0 {
+			fmt.Fprintf(w, "<h1>%s</h1>\n", esc(cmd.path))
+		} else {
+			fmt.Fprintf(w, "<h2>%s</h2>\n", esc(cmd.path))
+		}
+		if cmd.help != "" {
+			fmt.Fprintf(w, "<p>%s</p>\n", esc(cmd.help))
+		}
+		if len(cmd.flags) == 0 {
+			fmt.Fprintln(w, "<p>No flags.</p>")
+			continue
+		}
+		fmt.Fprintln(w, "<table>")
+		fmt.Fprintln(w, "<tr><th>Flag</th><th>Shorthand</th><th>Type</th><th>Default</th><th>Description</th><th>Deprecated</th><th>Hidden</th></tr>")
+		for _, f := range cmd.flags {
+			short := ""
+			if f.short != "" {
+				short = "<code>-" + esc(f.short) + "</code>"
+			}
+			def := ""
+			if f.def != "" {
+				def = "<code>" + esc(f.def) + "</code>"
+			}
+			fmt.Fprintf(w, "<tr><td><code>--%s</code></td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				esc(f.name), short, esc(f.typ), def, esc(f.help), esc(f.depr), yesNo(f.hidden),
+			)
+		}
+		fmt.Fprintln(w, "</table>")
+	}
+	fmt.Fprintln(w, "</body>")
+	fmt.Fprintln(w, "</html>")
+}
+
+// mdEscape escapes the text to be safely used in a Markdown table cell.
+func mdEscape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`",
+		"<", "&lt;", ">", "&gt;", "\n", " ",
+	)
+	return r.Replace(s)
+}
+
+// mdCode formats the text as inline code in a Markdown table cell.
+func mdCode(s string) string {
+	if strings.Contains(s, "`") {
+		return mdEscape(s)
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,116 @@
+package cliff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+type docsConfig struct {
+	verbose bool
+	port    int
+	name    string
+	old     string
+	secret  string
+}
+
+func docsSpec(c *docsConfig) cliff.Spec {
+	serve := func(c *docsConfig) cliff.Flags {
+		return cliff.Flags{
+			"port": cliff.F(&c.port, 'p', 8080, "port to listen to"),
+		}
+	}
+	return cliff.Spec{
+		Flags: cliff.Flags{
+			"verbose": cliff.F(&c.verbose, 'v', false, "enable | verbose output").Persistent(),
+			"name":    cliff.F(&c.name, 0, "<anon>", "user name"),
+			"old":     cliff.F(&c.old, 0, "", "").Deprecated("use --name"),
+			"secret":  cliff.F(&c.secret, 0, "", "for debugging").Hidden(),
+		},
+		Commands: cliff.Commands{
+			"serve":   cliff.Cmd(serve, nil, "run the server"),
+			"version": cliff.Cmd(func(c *docsConfig) cliff.Flags { return nil }, nil, ""),
+		},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := cliff.Markdown(&buf, "my_app", docsSpec)
+	is.NoErr(err)
+	expected := "# my\\_app\n" +
+		"\n" +
+		"| Flag | Shorthand | Type | Default | Description | Deprecated | Hidden |\n" +
+		"| ---- | --------- | ---- | ------- | ----------- | ---------- | ------ |\n" +
+		"| `--name` |  | string | `<anon>` | user name |  | no |\n" +
+		"| `--old` |  | string |  |  | use --name | no |\n" +
+		"| `--secret` |  | string |  | for debugging |  | yes |\n" +
+		"| `--verbose` | `-v` |  |  | enable \\| verbose output |  | no |\n" +
+		"\n" +
+		"## my\\_app serve\n" +
+		"\n" +
+		"run the server\n" +
+		"\n" +
+		"| Flag | Shorthand | Type | Default | Description | Deprecated | Hidden |\n" +
+		"| ---- | --------- | ---- | ------- | ----------- | ---------- | ------ |\n" +
+		"| `--port` | `-p` | int | `8080` | port to listen to |  | no |\n" +
+		"\n" +
+		"## my\\_app version\n" +
+		"\n" +
+		"No flags.\n"
+	is.Equal(buf.String(), expected)
+
+	// The output must be deterministic.
+	var buf2 bytes.Buffer
+	err = cliff.Markdown(&buf2, "my_app", docsSpec)
+	is.NoErr(err)
+	is.Equal(buf.String(), buf2.String())
+}
+
+func TestHTML(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := cliff.HTML(&buf, "<app>", func(c *docsConfig) cliff.Flags {
+		return cliff.Flags{
+			"name": cliff.F(&c.name, 'n', "a&b", "user <name>"),
+		}
+	})
+	is.NoErr(err)
+	expected := `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>&lt;app&gt;</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+</style>
+</head>
+<body>
+<h1>&lt;app&gt;</h1>
+<table>
+<tr><th>Flag</th><th>Shorthand</th><th>Type</th><th>Default</th><th>Description</th><th>Deprecated</th><th>Hidden</th></tr>
+<tr><td><code>--name</code></td><td><code>-n</code></td><td>string</td><td><code>a&amp;b</code></td><td>user &lt;name&gt;</td><td></td><td>no</td></tr>
+</table>
+</body>
+</html>
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestMarkdown_Error(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := cliff.Markdown(&buf, "app", func(c *docsConfig) cliff.Spec {
+		return cliff.Spec{Commands: cliff.Commands{"bad name": cliff.Command{}}}
+	})
+	is.Equal(err.Error(), "validate command name (bad name): can contain only alpha-numeric ASCII characters and dashes")
+}
@@ -0,0 +1,87 @@
+package cliff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// annotationEnv is the [pflag.Flag] annotation holding the env var name.
+const annotationEnv = "cliff-env"
+
+var isValidEnv = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString
+
+// envName generates env var name for the flag with the given name and prefix.
+//
+// For example, flag "dry-run" with prefix "APP" becomes "APP_DRY_RUN".
+func envName(prefix, name string) string {
+	name = strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return prefix + "_" + name
+}
+
+// getEnv returns the name of the env var associated with the flag, if any.
+func getEnv(pf *pflag.Flag) string {
+	env := pf.Annotations[annotationEnv]
+	if len(env) == 0 {
+		return ""
+	}
+	return env[0]
+}
+
+// setEnvPrefix associates an env var with every flag that doesn't have one.
+func setEnvPrefix(pfs *pflag.FlagSet, flags Flags, prefix string) error {
+	if !isValidEnv(prefix) {
+		return fmt.Errorf("invalid env prefix: %s", prefix)
+	}
+	for name := range flags {
+		if getEnv(pfs.Lookup(name)) != "" {
+			continue
+		}
+		err := pfs.SetAnnotation(name, annotationEnv, []string{envName(prefix, name)})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEnv sets values from env vars for the flags that were not passed explicitly.
+//
+// Only the given flags are updated, inherited flags are handled by the parent command.
+// Names of all updated flags are added into the given set.
+func applyEnv(pfs *pflag.FlagSet, flags Flags, lookup func(string) (string, bool), set map[*pflag.Flag]Source) error {
+	var err error
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		_, own := flags[pf.Name]
+		env := getEnv(pf)
+		if err != nil || !own || env == "" || pf.Changed {
+			return
+		}
+		raw, found := lookup(env)
+		if !found {
+			return
+		}
+		setErr := pf.Value.Set(raw)
+		if setErr != nil {
+			err = InvalidValueError{
+				Name:  pf.Name,
+				Value: raw,
+				Err:   setErr,
+				msg:   fmt.Sprintf("invalid argument %q for %q flag from env var %s: %v", raw, flagName(pf), env, setErr),
+			}
+			return
+		}
+		set[pf] = SourceEnv
+	})
+	return err
+}
+
+// flagName returns the flag name as pflag shows it in errors.
+func flagName(pf *pflag.Flag) string {
+	if pf.Shorthand != "" && pf.ShorthandDeprecated == "" {
+		return fmt.Sprintf("-%s, --%s", pf.Shorthand, pf.Name)
+	}
+	return "--" + pf.Name
+}
@@ -0,0 +1,123 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestEnv(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		host    string
+		port    int
+		tags    []string
+		timeout time.Duration
+		verbose cliff.Count
+	}
+	env := map[string]string{
+		"APP_HOST":    "localhost",
+		"APP_PORT":    "80",
+		"APP_TAGS":    "a,b",
+		"APP_TIMEOUT": "3s",
+		"VERBOSITY":   "2",
+	}
+	var config Config
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"host":    cliff.F(&config.host, 0, "127.0.0.1", ""),
+			"port":    cliff.F(&config.port, 'p', 8080, ""),
+			"tags":    cliff.F(&config.tags, 0, []string{"x"}, ""),
+			"timeout": cliff.F(&config.timeout, 0, time.Second, ""),
+			"verbose": cliff.F(&config.verbose, 'v', 0, "").Env("VERBOSITY"),
+		},
+		EnvPrefix: "APP",
+		LookupEnv: func(key string) (string, bool) {
+			val, found := env[key]
+			return val, found
+		},
+	}
+
+	err := spec.Parse(io.Discard, []string{"example", "-p", "8000", "--tags", "c"})
+	is.NoErr(err)
+	expected := Config{
+		host:    "localhost",
+		port:    8000,
+		tags:    []string{"c"},
+		timeout: 3 * time.Second,
+		verbose: 2,
+	}
+	is.Equal(config, expected)
+
+	env["APP_PORT"] = "eighty"
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), `invalid argument "eighty" for "-p, --port" flag from env var APP_PORT: strconv.ParseInt: parsing "eighty": invalid syntax`)
+}
+
+func TestEnv_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var host string
+	err := cliff.Spec{
+		Flags: cliff.Flags{"host": cliff.F(&host, 0, "", "").Env("APP-HOST")},
+	}.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "add flag host: env var name can contain only alpha-numeric ASCII characters and underscores")
+
+	err = cliff.Spec{
+		Flags:     cliff.Flags{"host": cliff.F(&host, 0, "", "")},
+		EnvPrefix: "my app",
+	}.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "invalid env prefix: my app")
+}
+
+func TestEnv_Subcommand(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		tags    []string
+		verbose cliff.Count
+		result  cliff.Result
+	}
+	run := func(args ...string) Config {
+		var c Config
+		serve := cliff.Cmd(func(*struct{}) cliff.Flags {
+			return cliff.Flags{}
+		}, func(struct{}) error { return nil }, "")
+		root := cliff.Cmd(func(*struct{}) cliff.Spec {
+			return cliff.Spec{
+				Flags: cliff.Flags{
+					"tags":    cliff.F(&c.tags, 0, nil, "").Env("TAGS").Persistent(),
+					"verbose": cliff.F(&c.verbose, 'v', 0, "").Env("V").Persistent(),
+				},
+				Commands: cliff.Commands{"serve": serve},
+				LookupEnv: func(key string) (string, bool) {
+					val, found := map[string]string{"TAGS": "a,b", "V": "2"}[key]
+					return val, found
+				},
+				Result: &c.result,
+			}
+		}, nil, "")
+		err := cliff.Run(io.Discard, append([]string{"example"}, args...), root)
+		is.NoErr(err)
+		return c
+	}
+
+	// Flags passed after the subcommand name replace env values.
+	c := run("serve", "--tags", "c", "-v")
+	is.Equal(c.tags, []string{"c"})
+	is.Equal(c.verbose, cliff.Count(1))
+	is.Equal(c.result.Source("tags"), cliff.SourceCLI)
+
+	c = run("--tags", "c", "-v", "serve")
+	is.Equal(c.tags, []string{"c"})
+	is.Equal(c.verbose, cliff.Count(1))
+
+	c = run("serve")
+	is.Equal(c.tags, []string{"a", "b"})
+	is.Equal(c.verbose, cliff.Count(2))
+	is.Equal(c.result.Source("tags"), cliff.SourceEnv)
+}
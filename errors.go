@@ -0,0 +1,222 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var missingLong = regexp.MustCompile(`^flag needs an argument: --(.+)$`).FindStringSubmatch
+var missingShort = regexp.MustCompile(`^flag needs an argument: '(.)' in -`).FindStringSubmatch
+
+// UnknownFlagError is returned when an unknown flag is passed.
+type UnknownFlagError struct {
+	// Name is the flag as passed, like "--hots" or "-q".
+	// For config files, it's the key from the file.
+	Name string
+
+	// Suggestions are known flags similar to the passed one, like "--host".
+	Suggestions []string
+
+	msg string
+}
+
+func (e UnknownFlagError) Error() string {
+	msg := e.msg
+	if msg == "" {
+		msg = "unknown flag: " + e.Name
+	}
+	if len(e.Suggestions) != 0 {
+		msg += "; did you mean " + strings.Join(e.Suggestions, " or ") + "?"
+	}
+	return msg
+}
+
+// MissingValueError is returned when a flag requiring a value is passed without one.
+type MissingValueError struct {
+	Name string // the long flag name
+
+	msg string
+}
+
+func (e MissingValueError) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return "flag needs an argument: --" + e.Name
+}
+
+// InvalidValueError is returned when a flag or a positional argument value cannot be parsed
+// or doesn't pass validation.
+type InvalidValueError struct {
+	// Name is the long flag name or the positional argument name in uppercase.
+	Name string
+
+	// Value is the raw input. For values rejected by validators,
+	// it's the parsed value formatted back into a string.
+	Value string
+
+	// Err is the error returned by the parser or the validator.
+	Err error
+
+	msg string
+}
+
+func (e InvalidValueError) Error() string {
+	if e.msg != "" {
+		return e.msg
+	}
+	return fmt.Sprintf("invalid argument %q for --%s flag: %v", e.Value, e.Name, e.Err)
+}
+
+func (e InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// MissingArgsError is returned when some of the required positional arguments are not passed.
+type MissingArgsError struct {
+	Names []string // names of all missing arguments in uppercase, in the order of definition
+}
+
+func (e MissingArgsError) Error() string {
+	if len(e.Names) == 1 {
+		return "missing required argument: " + e.Names[0]
+	}
+	return "missing required arguments: " + strings.Join(e.Names, ", ")
+}
+
+// UnexpectedArgsError is returned when more positional arguments are passed than defined.
+type UnexpectedArgsError struct {
+	Args []string // all extra arguments
+}
+
+func (e UnexpectedArgsError) Error() string {
+	if len(e.Args) == 1 {
+		return "unexpected argument: " + e.Args[0]
+	}
+	return "unexpected arguments: " + strings.Join(e.Args, ", ")
+}
+
+// UnknownCommandError is returned when an unknown subcommand is passed.
+type UnknownCommandError struct {
+	// Name is the subcommand name as passed.
+	Name string
+
+	// Suggestions are known subcommands similar to the passed one.
+	Suggestions []string
+}
+
+func (e UnknownCommandError) Error() string {
+	msg := "unknown command: " + e.Name
+	if len(e.Suggestions) != 0 {
+		msg += "; did you mean " + strings.Join(e.Suggestions, " or ") + "?"
+	}
+	return msg
+}
+
+// MissingCommandError is returned when a subcommand is required but not passed.
+type MissingCommandError struct {
+	Commands []string // sorted names of all available subcommands
+}
+
+func (e MissingCommandError) Error() string {
+	return "missing command"
+}
+
+// DefinitionError is returned when the CLI itself is defined incorrectly.
+//
+// For example, when a flag name is invalid or two flags have the same shorthand.
+// It's a bug in the program, not a mistake of the user.
+type DefinitionError struct {
+	Err error
+}
+
+func (e DefinitionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e DefinitionError) Unwrap() error {
+	return e.Err
+}
+
+// defError wraps the error into [DefinitionError] if it's not wrapped yet.
+func defError(err error) error {
+	var de DefinitionError
+	if err == nil || errors.As(err, &de) {
+		return err
+	}
+	return DefinitionError{Err: err}
+}
+
+// failedSet is the last failed attempt to set a flag value.
+type failedSet struct {
+	flag *pflag.Flag
+	raw  string
+	err  error
+}
+
+// recordingValue is a [pflag.Value] remembering the error from a failed [pflag.Value.Set].
+//
+// pflag formats the error from the value into a string,
+// so the original error would be lost otherwise.
+type recordingValue struct {
+	pflag.Value
+	flag   *pflag.Flag
+	failed *failedSet
+}
+
+func (v recordingValue) Set(raw string) error {
+	err := v.Value.Set(raw)
+	if err != nil {
+		*v.failed = failedSet{flag: v.flag, raw: raw, err: err}
+	}
+	return err
+}
+
+// recordFailures wraps values of all flags into [recordingValue].
+//
+// The returned function restores the original values.
+func recordFailures(pfs *pflag.FlagSet, failed *failedSet) func() {
+	orig := make(map[*pflag.Flag]pflag.Value)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		orig[pf] = pf.Value
+		pf.Value = recordingValue{Value: pf.Value, flag: pf, failed: failed}
+	})
+	return func() {
+		for pf, val := range orig {
+			pf.Value = val
+		}
+	}
+}
+
+// parseError converts the error returned by [pflag.FlagSet.Parse] into a typed error.
+func parseError(pfs *pflag.FlagSet, err error, failed failedSet) error {
+	msg := err.Error()
+	if failed.err != nil {
+		return InvalidValueError{
+			Name:  failed.flag.Name,
+			Value: failed.raw,
+			Err:   failed.err,
+			msg:   msg,
+		}
+	}
+	if m := unknownLong(msg); m != nil {
+		return UnknownFlagError{Name: "--" + m[1], Suggestions: suggestFlags(pfs, msg), msg: msg}
+	}
+	if m := unknownShort(msg); m != nil {
+		return UnknownFlagError{Name: "-" + m[1], Suggestions: suggestFlags(pfs, msg), msg: msg}
+	}
+	if m := missingLong(msg); m != nil {
+		return MissingValueError{Name: m[1], msg: msg}
+	}
+	if m := missingShort(msg); m != nil {
+		pf := pfs.ShorthandLookup(m[1])
+		if pf != nil {
+			return MissingValueError{Name: pf.Name, msg: msg}
+		}
+	}
+	return err
+}
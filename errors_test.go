@@ -0,0 +1,173 @@
+package cliff_test
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestErrors(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var host, name string
+	var src int
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"port": cliff.F(&port, 'p', 8080, "", cliff.Positive[int]),
+			"host": cliff.F(&host, 0, "", "").Env("HOST"),
+			"name": cliff.F(&name, 'n', "", "").Required(),
+		},
+		Args: cliff.Args{cliff.A(&src, "src", 0, "")},
+		LookupEnv: func(key string) (string, bool) {
+			return "", false
+		},
+	}
+	parse := func(args ...string) error {
+		return spec.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+
+	var unknown cliff.UnknownFlagError
+	err := parse("--prot", "80")
+	is.True(errors.As(err, &unknown))
+	is.Equal(unknown.Name, "--prot")
+	is.Equal(unknown.Suggestions, []string{"--port"})
+	is.Equal(err.Error(), "unknown flag: --prot; did you mean --port?")
+
+	err = parse("-q")
+	is.True(errors.As(err, &unknown))
+	is.Equal(unknown.Name, "-q")
+	is.Equal(len(unknown.Suggestions), 0)
+
+	var missing cliff.MissingValueError
+	err = parse("--port")
+	is.True(errors.As(err, &missing))
+	is.Equal(missing.Name, "port")
+	is.Equal(err.Error(), "flag needs an argument: --port")
+
+	err = parse("-n")
+	is.True(errors.As(err, &missing))
+	is.Equal(missing.Name, "name")
+
+	var invalid cliff.InvalidValueError
+	var numErr *strconv.NumError
+	err = parse("-p", "eighty")
+	is.True(errors.As(err, &invalid))
+	is.Equal(invalid.Name, "port")
+	is.Equal(invalid.Value, "eighty")
+	is.True(errors.As(err, &numErr))
+	is.Equal(err.Error(), `invalid argument "eighty" for "-p, --port" flag: strconv.ParseInt: parsing "eighty": invalid syntax`)
+
+	err = parse("-n", "joe", "-p", "-1")
+	is.True(errors.As(err, &invalid))
+	is.Equal(invalid.Name, "port")
+	is.Equal(invalid.Value, "-1")
+	is.Equal(invalid.Err.Error(), "must be positive")
+
+	err = parse("-n", "joe", "one")
+	is.True(errors.As(err, &invalid))
+	is.Equal(invalid.Name, "SRC")
+	is.Equal(invalid.Value, "one")
+
+	var required cliff.MissingFlagsError
+	err = parse()
+	is.True(errors.As(err, &required))
+	is.Equal(required.Names, []string{"name"})
+
+	var def cliff.DefinitionError
+	spec.Flags["Bad"] = cliff.F(&host, 0, "", "")
+	err = parse()
+	is.True(errors.As(err, &def))
+	is.Equal(err.Error(), "validate flag name (Bad): must be lowercase")
+}
+
+func TestErrors_EnvAndConfig(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var config string
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"port":   cliff.F(&port, 0, 0, "").Env("PORT"),
+			"config": cliff.F(&config, 0, "", ""),
+		},
+		ConfigFlag: "config",
+		LookupEnv: func(key string) (string, bool) {
+			return "abc", key == "PORT"
+		},
+		ReadFile: func(path string) ([]byte, error) {
+			return []byte(`{"hots": 1}`), nil
+		},
+	}
+	var invalid cliff.InvalidValueError
+	err := spec.Parse(io.Discard, []string{"example"})
+	is.True(errors.As(err, &invalid))
+	is.Equal(invalid.Name, "port")
+	is.Equal(invalid.Value, "abc")
+
+	var unknown cliff.UnknownFlagError
+	err = spec.Parse(io.Discard, []string{"example", "--port", "1", "--config", "c.json"})
+	is.True(errors.As(err, &unknown))
+	is.Equal(unknown.Name, "hots")
+	is.Equal(err.Error(), "c.json:1: unknown flag: hots")
+
+	var def cliff.DefinitionError
+	spec.ConfigFlag = "port"
+	err = spec.Parse(io.Discard, []string{"example", "--port", "1"})
+	is.True(errors.As(err, &def))
+}
+
+func TestErrors_ArgsAndCommands(t *testing.T) {
+	is := is.New(t)
+
+	var src, dst, opt string
+	args := cliff.Spec{
+		Args: cliff.Args{
+			cliff.A(&src, "src", "", "").Required(),
+			cliff.A(&dst, "dst", "", "").Required(),
+			cliff.A(&opt, "opt", "", ""),
+		},
+	}
+	parse := func(spec cliff.Spec, args ...string) error {
+		return spec.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+
+	var missing cliff.MissingArgsError
+	err := parse(args)
+	is.True(errors.As(err, &missing))
+	is.Equal(missing.Names, []string{"SRC", "DST"})
+	is.Equal(err.Error(), "missing required arguments: SRC, DST")
+
+	err = parse(args, "a")
+	is.True(errors.As(err, &missing))
+	is.Equal(missing.Names, []string{"DST"})
+	is.Equal(err.Error(), "missing required argument: DST")
+
+	var unexpected cliff.UnexpectedArgsError
+	err = parse(args, "a", "b", "c", "d", "e")
+	is.True(errors.As(err, &unexpected))
+	is.Equal(unexpected.Args, []string{"d", "e"})
+	is.Equal(err.Error(), "unexpected arguments: d, e")
+
+	noop := cliff.Cmd(func(*struct{}) cliff.Flags { return nil }, func(struct{}) error { return nil }, "")
+	cmds := cliff.Spec{
+		Commands: cliff.Commands{"serve": noop, "server": noop, "status": noop},
+	}
+
+	var unknown cliff.UnknownCommandError
+	err = parse(cmds, "serv")
+	is.True(errors.As(err, &unknown))
+	is.Equal(unknown.Name, "serv")
+	is.Equal(unknown.Suggestions, []string{"serve", "server"})
+	is.Equal(err.Error(), "unknown command: serv; did you mean serve or server?")
+
+	var noCmd cliff.MissingCommandError
+	err = parse(cmds)
+	is.True(errors.As(err, &noCmd))
+	is.Equal(noCmd.Commands, []string{"serve", "server", "status"})
+	is.Equal(err.Error(), "missing command")
+}
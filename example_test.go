@@ -2,9 +2,14 @@ package cliff_test
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/netip"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/orsinium-labs/cliff"
 	"github.com/spf13/pflag"
@@ -45,6 +50,27 @@ func ExampleFuncFlag() {
 	// Output: localhost
 }
 
+func ExampleTextFlag() {
+	type Config struct{ addr netip.Addr }
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"addr": cliff.TextFlag(&c.addr, 0, netip.MustParseAddr("127.0.0.1"), "address to listen on"),
+		}
+	}
+	args := []string{"example", "--addr", "::1"}
+	config := cliff.MustParse(os.Stdout, os.Exit, args, flags)
+	fmt.Println(config.addr)
+
+	_, _ = cliff.Parse(os.Stdout, []string{"example", "--help"}, flags)
+
+	// Output:
+	// ::1
+	// Usage: example [flags]
+	//
+	// Flags:
+	//       --addr addr   address to listen on (default 127.0.0.1)
+}
+
 func ExampleMustParse() {
 	type Config struct{ host string }
 	flags := func(c *Config) cliff.Flags {
@@ -127,6 +153,48 @@ func ExampleFlag_ShortDeprecated() {
 	// localhost
 }
 
+func ExampleFlag_Alias() {
+	type Config struct{ listen string }
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"listen": cliff.F(&c.listen, 0, ":8080", "address to listen on").Alias("addr"),
+		}
+	}
+	args := []string{"example", "--addr", ":80"}
+	config := cliff.MustParse(os.Stdout, os.Exit, args, flags)
+	fmt.Println(config.listen)
+
+	_, _ = cliff.Parse(os.Stdout, []string{"example", "--help"}, flags)
+
+	// Output:
+	// :80
+	// Usage: example [flags]
+	//
+	// Flags:
+	//       --listen, --addr string   address to listen on (default ":8080")
+}
+
+func ExampleFlag_Negatable() {
+	type Config struct{ color bool }
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"color": cliff.F(&c.color, 0, true, "colorize the output").Negatable(),
+		}
+	}
+	args := []string{"example", "--no-color"}
+	config := cliff.MustParse(os.Stdout, os.Exit, args, flags)
+	fmt.Println(config.color)
+
+	_, _ = cliff.Parse(os.Stdout, []string{"example", "--help"}, flags)
+
+	// Output:
+	// false
+	// Usage: example [flags]
+	//
+	// Flags:
+	//       --[no-]color   colorize the output (default true)
+}
+
 func ExampleFlags_FlagSet() {
 	var host string
 	var debug bool
@@ -195,6 +263,18 @@ func ExampleFlags_PFlagSet() {
 	// Output: localhost
 }
 
+func ExampleFlags_CheckConflicts() {
+	var port, profile int
+	flags := cliff.Flags{
+		"port":    cliff.F(&port, 'p', 8080, "port to listen to"),
+		"profile": cliff.F(&profile, 'p', 0, "profile to use"),
+	}
+	err := flags.CheckConflicts(nil)
+	fmt.Println(err)
+	// Output:
+	// conflicting flags: -p is used by flag --port and flag --profile
+}
+
 func ExampleFlags_Parse() {
 	type Config struct{ host string }
 	var config Config
@@ -253,3 +333,574 @@ func ExampleBytesBase64() {
 	fmt.Println(config.data)
 	// Output: [97]
 }
+
+func ExampleA() {
+	type Config struct {
+		src string
+		dst string
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Args: cliff.Args{
+				cliff.A(&c.src, "src", "", "file to copy").Required(),
+				cliff.A(&c.dst, "dst", ".", "where to copy the file"),
+			},
+		}
+	}
+	args := []string{"example", "main.go"}
+	config := cliff.MustParse(os.Stderr, os.Exit, args, flags)
+	fmt.Println(config.src, config.dst)
+	// Output: main.go .
+}
+
+func ExampleArg_Variadic() {
+	type Config struct {
+		files []string
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Args: cliff.Args{
+				cliff.A(&c.files, "files", nil, "files to check").Required().Variadic(),
+			},
+		}
+	}
+	args := []string{"example", "a.go", "b,c.go"}
+	config := cliff.MustParse(os.Stderr, os.Exit, args, flags)
+	fmt.Printf("%q\n", config.files)
+	// Output: ["a.go" "b,c.go"]
+}
+
+func ExampleSpec() {
+	type Config struct {
+		force bool
+		src   string
+		dst   string
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"force": cliff.F(&c.force, 'f', false, "overwrite existing files"),
+			},
+			Args: cliff.Args{
+				cliff.A(&c.src, "src", "", "file to copy").Required(),
+				cliff.A(&c.dst, "dst", ".", "where to copy the file"),
+			},
+		}
+	}
+	args := []string{"example", "--help"}
+	_, err := cliff.Parse(os.Stdout, args, flags)
+	fmt.Println(err)
+	// Output:
+	// Usage: example [flags] SRC [DST]
+	//
+	// Arguments:
+	//   SRC   file to copy
+	//   DST   where to copy the file (default .)
+	//
+	// Flags:
+	//   -f, --force   overwrite existing files
+	// pflag: help requested
+}
+
+func ExampleCmd() {
+	type Global struct{ verbose bool }
+	type Serve struct{ port int }
+	root := cliff.Cmd(func(g *Global) cliff.Spec {
+		serve := func(c Serve) error {
+			fmt.Println("serving on", c.port, "verbose:", g.verbose)
+			return nil
+		}
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"verbose": cliff.F(&g.verbose, 'v', false, "show more output").Persistent(),
+			},
+			Commands: cliff.Commands{
+				"serve": cliff.Cmd(func(c *Serve) cliff.Flags {
+					return cliff.Flags{
+						"port": cliff.F(&c.port, 'p', 8080, "port to listen to"),
+					}
+				}, serve, "run the server"),
+			},
+		}
+	}, nil, "")
+	args := []string{"example", "serve", "-p", "80", "-v"}
+	cliff.MustRun(os.Stderr, os.Exit, args, root)
+	// Output: serving on 80 verbose: true
+}
+
+func ExampleRun() {
+	type Config struct{ name string }
+	greet := func(c Config) error {
+		fmt.Printf("Hello, %s!\n", c.name)
+		return nil
+	}
+	cmd := cliff.Cmd(func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"name": cliff.F(&c.name, 'n', "world", "who to greet"),
+		}
+	}, greet, "")
+	args := []string{"example", "--name", "Gopher"}
+	err := cliff.Run(os.Stderr, args, cmd)
+	cliff.HandleError(os.Stderr, os.Exit, err)
+	// Output: Hello, Gopher!
+}
+
+func ExampleMustRun() {
+	type Config struct{}
+	noop := func(c Config) error { return nil }
+	root := cliff.Cmd(func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Commands: cliff.Commands{
+				"check":   cliff.Cmd(func(c *Config) cliff.Flags { return nil }, noop, "check the config"),
+				"migrate": cliff.Cmd(func(c *Config) cliff.Flags { return nil }, noop, "apply migrations"),
+			},
+		}
+	}, nil, "")
+	args := []string{"example", "--help"}
+	cliff.MustRun(os.Stdout, func(int) {}, args, root)
+	// Output:
+	// Usage: example COMMAND
+	//
+	// Commands:
+	//   check     check the config
+	//   migrate   apply migrations
+}
+
+func ExampleFlag_Env() {
+	type Config struct {
+		host string
+		port int
+	}
+	env := map[string]string{"APP_HOST": "localhost", "APP_PORT": "80"}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"host": cliff.F(&c.host, 0, "127.0.0.1", "host to serve on").Env("APP_HOST"),
+				"port": cliff.F(&c.port, 'p', 8080, "port to listen to").Env("APP_PORT"),
+			},
+			LookupEnv: func(key string) (string, bool) {
+				val, found := env[key]
+				return val, found
+			},
+		}
+	}
+	args := []string{"example", "--port", "8000"}
+	config := cliff.MustParse(os.Stderr, os.Exit, args, flags)
+	fmt.Println(config.host, config.port)
+	// Output: localhost 8000
+}
+
+func ExampleSpec_envPrefix() {
+	type Config struct {
+		host   string
+		dryRun bool
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"host":    cliff.F(&c.host, 0, "127.0.0.1", "host to serve on"),
+				"dry-run": cliff.F(&c.dryRun, 'n', false, "don't change anything"),
+			},
+			EnvPrefix: "APP",
+		}
+	}
+	args := []string{"example", "--help"}
+	_, _ = cliff.Parse(os.Stdout, args, flags)
+	// Output:
+	// Usage: example [flags]
+	//
+	// Flags:
+	//   -n, --dry-run       don't change anything [$APP_DRY_RUN]
+	//       --host string   host to serve on (default "127.0.0.1") [$APP_HOST]
+}
+
+func ExampleSpec_config() {
+	type Config struct {
+		config string
+		host   string
+		port   int
+	}
+	files := map[string]string{
+		"app.ini": "host = localhost\nport = 80\n",
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"config": cliff.F(&c.config, 'c', "app.ini", "path to the config file"),
+				"host":   cliff.F(&c.host, 0, "127.0.0.1", "host to serve on"),
+				"port":   cliff.F(&c.port, 'p', 8080, "port to listen to"),
+			},
+			ConfigFlag: "config",
+			ReadFile: func(path string) ([]byte, error) {
+				return []byte(files[path]), nil
+			},
+		}
+	}
+	args := []string{"example", "--port", "8000"}
+	config := cliff.MustParse(os.Stderr, os.Exit, args, flags)
+	fmt.Println(config.host, config.port)
+	// Output: localhost 8000
+}
+
+func ExampleR() {
+	type Config struct {
+		host string
+		port int
+	}
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"host": cliff.R(&c.host, 0, "host to serve on"),
+			"port": cliff.F(&c.port, 'p', 8080, "port to listen to").Required(),
+		}
+	}
+	args := []string{"example", "--help"}
+	_, _ = cliff.Parse(os.Stdout, args, flags)
+	_, err := cliff.Parse(os.Stdout, []string{"example"}, flags)
+	fmt.Println(err)
+	// Output:
+	// Usage: example [flags]
+	//
+	// Flags:
+	//       --host string   host to serve on (required)
+	//   -p, --port int      port to listen to (default 8080) (required)
+	// missing required flags: --host, --port
+}
+
+func ExampleFlag_Required() {
+	type Config struct{ token string }
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"token": cliff.F(&c.token, 't', "", "API token").Required(),
+		}
+	}
+	args := []string{"example"}
+	_, err := cliff.Parse(os.Stderr, args, flags)
+	var missing cliff.MissingFlagsError
+	if errors.As(err, &missing) {
+		fmt.Println(missing.Names)
+	}
+	// Output: [token]
+}
+
+func ExampleInRange() {
+	type Config struct{ port int }
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"port": cliff.F(&c.port, 'p', 8080, "port to listen to", cliff.InRange(1, 65535)),
+		}
+	}
+	args := []string{"example", "--port", "100000"}
+	_, err := cliff.Parse(os.Stderr, args, flags)
+	fmt.Println(err)
+	// Output: invalid value for --port: must be between 1 and 65535
+}
+
+func ExampleNonEmpty() {
+	type Config struct{ name string }
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"name": cliff.F(&c.name, 'n', "", "user name", cliff.NonEmpty[string]),
+		}
+	}
+	args := []string{"example", "--name", ""}
+	_, err := cliff.Parse(os.Stderr, args, flags)
+	fmt.Println(err)
+	// Output: invalid value for --name: must not be empty
+}
+
+func ExampleEnum() {
+	type Format string
+	type Config struct{ format Format }
+	flags := func(c *Config) cliff.Flags {
+		formats := map[string]Format{
+			"json": "json",
+			"yaml": "yaml",
+			"text": "text",
+		}
+		return cliff.Flags{
+			"format": cliff.Enum(&c.format, 'f', "text", formats, "output format"),
+		}
+	}
+	_, _ = cliff.Parse(os.Stdout, []string{"example", "--help"}, flags)
+	_, err := cliff.Parse(os.Stdout, []string{"example", "-f", "ymal"}, flags)
+	fmt.Println(err)
+	// Output:
+	// Usage: example [flags]
+	//
+	// Flags:
+	//   -f, --format string   output format (one of: json, text, yaml)
+	//                         (default "text")
+	// invalid argument "ymal" for "-f, --format" flag: must be one of: json, text, yaml; did you mean yaml?
+}
+
+func ExampleExactlyOne() {
+	type Config struct {
+		token     string
+		tokenFile string
+		tlsCert   string
+		tlsKey    string
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"token":      cliff.F(&c.token, 0, "", "API token"),
+				"token-file": cliff.F(&c.tokenFile, 0, "", "path to the file with API token"),
+				"tls-cert":   cliff.F(&c.tlsCert, 0, "", "path to TLS certificate"),
+				"tls-key":    cliff.F(&c.tlsKey, 0, "", "path to TLS key"),
+			},
+			Groups: []cliff.Group{
+				cliff.ExactlyOne("token", "token-file"),
+				cliff.Requires("tls-cert", "tls-key"),
+			},
+		}
+	}
+	_, _ = cliff.Parse(os.Stdout, []string{"example", "--help"}, flags)
+	_, err := cliff.Parse(os.Stdout, []string{"example", "--tls-cert", "cert.pem"}, flags)
+	fmt.Println(err)
+	// Output:
+	// Usage: example [flags]
+	//
+	// Flags:
+	//       --tls-cert string     path to TLS certificate
+	//       --tls-key string      path to TLS key
+	//       --token string        API token
+	//       --token-file string   path to the file with API token
+	//
+	// Flag groups:
+	//   exactly one of --token, --token-file
+	//   --tls-cert requires --tls-key
+	// exactly one of the flags must be set: --token, --token-file
+}
+
+func ExampleSection() {
+	type Config struct {
+		host    string
+		port    int
+		timeout time.Duration
+		debug   bool
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"host":    cliff.F(&c.host, 0, "127.0.0.1", "host to serve on"),
+				"port":    cliff.F(&c.port, 'p', 8080, "port to listen to"),
+				"timeout": cliff.F(&c.timeout, 0, time.Minute, "request timeout"),
+				"debug":   cliff.F(&c.debug, 'd', false, "run in debug mode"),
+			},
+			Sections: []cliff.Section{
+				{Title: "Network", Flags: []string{"port", "host", "timeout"}},
+			},
+		}
+	}
+	_, _ = cliff.Parse(os.Stdout, []string{"example", "--help"}, flags)
+	// Output:
+	// Usage: example [flags]
+	//
+	// Network:
+	//   -p, --port int           port to listen to (default 8080)
+	//       --host string        host to serve on (default "127.0.0.1")
+	//       --timeout duration   request timeout (default 1m0s)
+	//
+	// Other flags:
+	//   -d, --debug   run in debug mode
+}
+
+func ExampleFlags_Completion() {
+	var format string
+	var config cliff.Path
+	var debug bool
+	flags := cliff.Flags{
+		"format": cliff.Enum(&format, 'f', "text", map[string]string{"json": "json", "text": "text"}, "output format"),
+		"config": cliff.F(&config, 'c', "", "path to the config file"),
+		"debug":  cliff.F(&debug, 0, false, "run in debug mode"),
+	}
+	err := flags.Completion(os.Stdout, cliff.Fish, "example")
+	cliff.HandleError(os.Stderr, os.Exit, err)
+	// Output:
+	// complete -c 'example' -l config -s c -d 'path to the config file' -r -F
+	// complete -c 'example' -l debug -d 'run in debug mode'
+	// complete -c 'example' -l format -s f -d 'output format' -x -a 'json text'
+}
+
+func ExampleSpec_completionFlag() {
+	type Config struct{ debug bool }
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"debug": cliff.F(&c.debug, 'd', false, "run in debug mode"),
+			},
+			CompletionFlag: "completion",
+			Stdout:         os.Stdout,
+		}
+	}
+	args := []string{"/usr/bin/example", "--completion", "bash"}
+	_, err := cliff.Parse(os.Stderr, args, flags)
+	fmt.Println(err)
+	// Output:
+	// _example_completion() {
+	//     local cur="${COMP_WORDS[COMP_CWORD]}"
+	//     local prev="${COMP_WORDS[COMP_CWORD-1]}"
+	//     case "$prev" in
+	//         --completion)
+	//             COMPREPLY=($(compgen -W 'bash fish powershell zsh' -- "$cur"))
+	//             return;;
+	//     esac
+	//     if [[ "$cur" == -* ]]; then
+	//         COMPREPLY=($(compgen -W '--completion --debug -d' -- "$cur"))
+	//     fi
+	// }
+	// complete -o default -F _example_completion 'example'
+	// completion requested
+}
+
+func ExampleFlag_Complete() {
+	var profile string
+	profiles := func(prefix string) []string {
+		candidates := make([]string, 0)
+		for _, p := range []string{"dev", "prod", "staging"} {
+			if strings.HasPrefix(p, prefix) {
+				candidates = append(candidates, p)
+			}
+		}
+		return candidates
+	}
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"profile": cliff.F(&profile, 'p', "dev", "profile to use").Complete(profiles),
+		},
+		Stdout: os.Stdout,
+	}
+	// This is how the completion script calls the program.
+	args := []string{"example", "__complete", "--profile", "d"}
+	err := spec.Parse(os.Stderr, args)
+	fmt.Println(err)
+	// Output:
+	// dev
+	// completion requested
+}
+
+func ExampleFlags_ManPage() {
+	var port int
+	flags := cliff.Flags{
+		"port": cliff.F(&port, 'p', 8080, "port to listen to"),
+	}
+	man := cliff.Man{Name: "example", Summary: "serve files", Manual: "User Commands"}
+	err := flags.ManPage(os.Stdout, man)
+	cliff.HandleError(os.Stderr, os.Exit, err)
+	// Output:
+	// .TH "EXAMPLE" 1 "" "" "User Commands"
+	// .SH NAME
+	// example \- serve files
+	// .SH SYNOPSIS
+	// .B example
+	// [\fIflags\fR]
+	// .SH OPTIONS
+	// .TP
+	// \fB\-p\fR, \fB\-\-port\fR \fIint\fR
+	// port to listen to
+	// .br
+	// Default: 8080.
+}
+
+func ExampleMarkdown() {
+	type Config struct {
+		port  int
+		debug bool
+	}
+	flags := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"port":  cliff.F(&c.port, 'p', 8080, "port to listen to"),
+			"debug": cliff.F(&c.debug, 0, false, "run in debug mode").Hidden(),
+		}
+	}
+	err := cliff.Markdown(os.Stdout, "example", flags)
+	cliff.HandleError(os.Stderr, os.Exit, err)
+	// Output:
+	// # example
+	//
+	// | Flag | Shorthand | Type | Default | Description | Deprecated | Hidden |
+	// | ---- | --------- | ---- | ------- | ----------- | ---------- | ------ |
+	// | `--debug` |  |  |  | run in debug mode |  | yes |
+	// | `--port` | `-p` | int | `8080` | port to listen to |  | no |
+}
+
+func ExampleHelpFunc() {
+	type Config struct{ port int }
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"port": cliff.F(&c.port, 'p', 8080, "port to listen to"),
+			},
+			Description: "Serve files from the current directory.",
+			Examples: []cliff.Example{
+				{Command: "example -p 80", Help: "serve on the default HTTP port"},
+			},
+			HelpRenderer: cliff.HelpFunc(func(w io.Writer, page cliff.HelpPage) error {
+				fmt.Fprintln(w, "Example v1.0.0")
+				fmt.Fprintln(w)
+				return cliff.DefaultHelp(w, page)
+			}),
+		}
+	}
+	args := []string{"example", "--help"}
+	_, err := cliff.Parse(os.Stdout, args, flags)
+	fmt.Println(err)
+	// Output:
+	// Example v1.0.0
+	//
+	// Usage: example [flags]
+	//
+	// Serve files from the current directory.
+	//
+	// Flags:
+	//   -p, --port int   port to listen to (default 8080)
+	//
+	// Examples:
+	//   # serve on the default HTTP port
+	//   example -p 80
+	// pflag: help requested
+}
+
+func ExampleSpec_versionFlag() {
+	type Config struct{ port int }
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"port": cliff.F(&c.port, 'p', 8080, "port to listen to"),
+			},
+			VersionFlag: "version",
+			Version:     "v1.2.3",
+			Stdout:      os.Stdout,
+		}
+	}
+	exit := func(code int) { fmt.Println("exit code:", code) }
+	args := []string{"example", "--version"}
+	cliff.MustParse(os.Stderr, exit, args, flags)
+	// Output:
+	// v1.2.3
+	// exit code: 0
+}
+
+func ExampleResult() {
+	type Config struct {
+		port   int
+		host   string
+		result cliff.Result
+	}
+	flags := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"port": cliff.F(&c.port, 'p', 8080, "port to listen to"),
+				"host": cliff.F(&c.host, 0, "127.0.0.1", "host to serve on"),
+			},
+			Result: &c.result,
+		}
+	}
+	args := []string{"example", "--port", "80"}
+	c := cliff.MustParse(os.Stderr, os.Exit, args, flags)
+	fmt.Println(c.result.Source("port"))
+	fmt.Println(c.result.Source("host"))
+	// Output:
+	// cli
+	// default
+}
@@ -0,0 +1,64 @@
+package cliff
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+)
+
+// ExitCodes are exit codes used by [ExitCodes.HandleError] for different kinds of errors.
+//
+// Zero values are replaced by the defaults.
+// Completion requests always exit with code 0.
+type ExitCodes struct {
+	// Usage is used when the user passed invalid arguments. Default: 2.
+	Usage int
+
+	// Definition is used for [DefinitionError],
+	// which means a bug in the program. Default: 70 (EX_SOFTWARE).
+	Definition int
+
+	// Help is used when help is requested. Default: 0.
+	Help int
+
+	// Version is used when the version is requested. Default: 0.
+	Version int
+}
+
+// HandleError interrupts the program if an error occurred when parsing arguments.
+//
+// The error is written into stderr and exit is called with the matching exit code.
+func (c ExitCodes) HandleError(stderr io.Writer, exit func(int), err error) {
+	if err == nil {
+		return
+	}
+	switch {
+	case err == pflag.ErrHelp || err == flag.ErrHelp:
+		exit(c.Help)
+		return
+	case err == ErrVersion:
+		exit(c.Version)
+		return
+	case err == ErrCompletion:
+		exit(0)
+		return
+	}
+	var defErr DefinitionError
+	if errors.As(err, &defErr) {
+		fmt.Fprintf(stderr, "bug in CLI definition: %v\n", err)
+		exit(withDefault(c.Definition, 70))
+		return
+	}
+	fmt.Fprintln(stderr, err)
+	exit(withDefault(c.Usage, 2))
+}
+
+func withDefault(code, def int) int {
+	if code == 0 {
+		return def
+	}
+	return code
+}
@@ -0,0 +1,109 @@
+package cliff_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+	"github.com/spf13/pflag"
+)
+
+func TestHandleError(t *testing.T) {
+	is := is.New(t)
+
+	handle := func(err error) (int, string) {
+		code := -1
+		var buf bytes.Buffer
+		cliff.HandleError(&buf, func(c int) { code = c }, err)
+		return code, buf.String()
+	}
+	code, out := handle(nil)
+	is.Equal(code, -1)
+	is.Equal(out, "")
+
+	code, _ = handle(pflag.ErrHelp)
+	is.Equal(code, 0)
+	code, _ = handle(cliff.ErrVersion)
+	is.Equal(code, 0)
+	code, _ = handle(cliff.ErrCompletion)
+	is.Equal(code, 0)
+
+	code, out = handle(errors.New("oh no"))
+	is.Equal(code, 2)
+	is.Equal(out, "oh no\n")
+
+	code, out = handle(cliff.DefinitionError{Err: errors.New("oh no")})
+	is.Equal(code, 70)
+	is.Equal(out, "bug in CLI definition: oh no\n")
+}
+
+func TestExitCodes(t *testing.T) {
+	is := is.New(t)
+
+	codes := cliff.ExitCodes{Usage: 64, Definition: 3, Help: 10, Version: 11}
+	handle := func(err error) int {
+		code := -1
+		codes.HandleError(io.Discard, func(c int) { code = c }, err)
+		return code
+	}
+	is.Equal(handle(pflag.ErrHelp), 10)
+	is.Equal(handle(cliff.ErrVersion), 11)
+	is.Equal(handle(cliff.ErrCompletion), 0)
+	is.Equal(handle(errors.New("oh no")), 64)
+	is.Equal(handle(cliff.DefinitionError{Err: errors.New("oh no")}), 3)
+}
+
+func TestMustParse_ExitCodes(t *testing.T) {
+	is := is.New(t)
+
+	var host string
+	init := func(c *struct{}) cliff.Spec {
+		return cliff.Spec{
+			Flags:     cliff.Flags{"Host": cliff.F(&host, 0, "", "")},
+			ExitCodes: cliff.ExitCodes{Definition: 99},
+		}
+	}
+	code := -1
+	var buf bytes.Buffer
+	cliff.MustParse(&buf, func(c int) { code = c }, []string{"example"}, init)
+	is.Equal(code, 99)
+	is.Equal(buf.String(), "bug in CLI definition: validate flag name (Host): must be lowercase\n")
+
+	// Definition errors in flags exit with code 70 by default.
+	flags := func(c *struct{}) cliff.Flags {
+		return cliff.Flags{"Host": cliff.F(&host, 0, "", "")}
+	}
+	cliff.MustParse(io.Discard, func(c int) { code = c }, []string{"example"}, flags)
+	is.Equal(code, 70)
+}
+
+func TestMustRun_ExitCodes(t *testing.T) {
+	is := is.New(t)
+
+	cmd := cliff.Cmd(func(c *struct{}) cliff.Spec {
+		return cliff.Spec{ExitCodes: cliff.ExitCodes{Usage: 64}}
+	}, func(struct{}) error { return nil }, "")
+	code := -1
+	cliff.MustRun(io.Discard, func(c int) { code = c }, []string{"example", "--unknown"}, cmd)
+	is.Equal(code, 64)
+
+	// The command is initialized once, the exit codes come from the same parse.
+	inits := 0
+	cmd = cliff.Cmd(func(c *struct{}) cliff.Spec {
+		inits++
+		return cliff.Spec{
+			ExitCodes: cliff.ExitCodes{Usage: 64},
+			Commands: cliff.Commands{
+				"sub": cliff.Cmd(func(c *struct{}) cliff.Spec {
+					return cliff.Spec{ExitCodes: cliff.ExitCodes{Usage: 65}}
+				}, func(struct{}) error { return errors.New("oh no") }, ""),
+			},
+		}
+	}, nil, "")
+	cliff.MustRun(io.Discard, func(c int) { code = c }, []string{"example", "sub"}, cmd)
+	is.Equal(inits, 1)
+	is.Equal(code, 64)
+}
@@ -1,6 +1,7 @@
 package cliff
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/pflag"
@@ -8,14 +9,24 @@ import (
 
 type setter interface {
 	AddTo(*pflag.FlagSet, string) error
+	shorthand() string
 }
 
 // Flag represents all info about a CLI flag except its name.
 type Flag struct {
 	setter    setter
-	depr      string // deprecation message
-	shortDepr string // deprecation message for the shorthand
-	hidden    bool   // don't show the flag in help
+	depr      string       // deprecation message
+	shortDepr string       // deprecation message for the shorthand
+	hidden    bool         // don't show the flag in help
+	persist   bool         // inherit the flag in subcommands
+	env       string       // env var to read the value from
+	required  bool         // the flag must be set
+	negatable bool         // add "--no-<name>" flag
+	aliases   []string     // additional long names for the flag
+	check     func() error // validate the parsed value
+
+	// provide completion candidates for the flag value
+	complete func(prefix string) []string
 }
 
 // Mark the flag as deprecated.
@@ -44,8 +55,71 @@ func (f Flag) Hidden() Flag {
 	return f
 }
 
+// Persistent makes the flag to be also available in all subcommands.
+//
+// The flag can be passed both before and after the subcommand name.
+func (f Flag) Persistent() Flag {
+	f.persist = true
+	return f
+}
+
+// Env sets the name of the environment variable to read the flag value from.
+//
+// The env var is used only if the flag is not passed explicitly
+// and it overrides the default value.
+// The value is parsed the same way as if it was passed as the flag value.
+func (f Flag) Env(name string) Flag {
+	f.env = name
+	return f
+}
+
+// Required makes the flag mandatory.
+//
+// The flag is considered set if it is passed explicitly,
+// via an env var, or in a config file.
+// If any of the required flags is not set, [Flags.Parse] returns [MissingFlagsError].
+func (f Flag) Required() Flag {
+	f.required = true
+	return f
+}
+
+// Negatable adds "--no-<name>" flag that sets the boolean flag to false.
+//
+// In help, both flags are shown together as "--[no-]name".
+// Passing both the flag and its negation is an error.
+func (f Flag) Negatable() Flag {
+	f.negatable = true
+	return f
+}
+
+// Alias adds alternative long names for the flag.
+//
+// Aliases share the target with the flag, so passing "--addr" is the same as
+// passing "--listen" if "addr" is an alias of "listen". In help,
+// aliases are shown next to the flag name.
+func (f Flag) Alias(names ...string) Flag {
+	f.aliases = append(append([]string{}, f.aliases...), names...)
+	return f
+}
+
+// Complete sets the function providing shell completion candidates for the flag value.
+//
+// The function is called with the part of the value already typed by the user.
+// Use it for values known only at runtime, like names of profiles from a local file.
+//
+// Completion scripts generated by [Flags.Completion] call the program
+// with "__complete" as the first argument, followed by the flag and the prefix,
+// and the program prints the candidates into stdout, one per line.
+func (f Flag) Complete(complete func(prefix string) []string) Flag {
+	f.complete = complete
+	return f
+}
+
 // AddTo adds the flag into the given [pflag.FlagSet] under the given name.
 func (f Flag) AddTo(fs *pflag.FlagSet, name string) error {
+	if f.setter == nil {
+		return errors.New("flag must be created using a constructor, like F")
+	}
 	err := f.setter.AddTo(fs, name)
 	if err != nil {
 		return err
@@ -63,11 +137,57 @@ func (f Flag) AddTo(fs *pflag.FlagSet, name string) error {
 			return fmt.Errorf("mark short deprecated: %v", err)
 		}
 	}
+	if f.env != "" {
+		if !isValidEnv(f.env) {
+			return errors.New("env var name can contain only alpha-numeric ASCII characters and underscores")
+		}
+		err = fs.SetAnnotation(name, annotationEnv, []string{f.env})
+		if err != nil {
+			return fmt.Errorf("set env: %v", err)
+		}
+	}
+	if f.required {
+		err = fs.SetAnnotation(name, annotationRequired, []string{"true"})
+		if err != nil {
+			return fmt.Errorf("mark required: %v", err)
+		}
+	}
+	if f.complete != nil {
+		err = fs.SetAnnotation(name, annotationComplete, []string{"true"})
+		if err != nil {
+			return fmt.Errorf("mark completable: %v", err)
+		}
+	}
 	if f.hidden {
 		err = fs.MarkHidden(name)
 		if err != nil {
 			return fmt.Errorf("mark hidden: %v", err)
 		}
 	}
+	if len(f.aliases) != 0 {
+		err = addAliases(fs, name, f.aliases)
+		if err != nil {
+			return err
+		}
+	}
+	if f.negatable {
+		err = addNegation(fs, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFree checks that the name and the shorthand are not used in the flag set yet.
+//
+// Otherwise, [pflag.FlagSet.AddFlag] panics.
+func checkFree(fs *pflag.FlagSet, name, short string) error {
+	if fs.Lookup(name) != nil {
+		return fmt.Errorf("flag %s is already defined", name)
+	}
+	if short != "" && fs.ShorthandLookup(short) != nil {
+		return fmt.Errorf("shorthand -%s is already used", short)
+	}
 	return nil
 }
@@ -0,0 +1,112 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// annotationChoices is the [pflag.Flag] annotation holding all allowed values.
+const annotationChoices = "cliff-choices"
+
+// tEnum represents all info about an enum CLI flag except its name.
+type tEnum[T comparable] struct {
+	tar     *T
+	def     T
+	choices map[string]T
+	short   string // short alias for the flag
+	help    string // usage message
+}
+
+// Enum creates a new flag that accepts only one of the given choices.
+//
+// The choices map the CLI values to the values to be written into the target.
+func Enum[T comparable](
+	tar *T,
+	short Short,
+	def T,
+	choices map[string]T,
+	help Help,
+) Flag {
+	shortStr := ""
+	if short != 0 {
+		shortStr = string(short)
+	}
+	setter := tEnum[T]{
+		tar:     tar,
+		def:     def,
+		choices: choices,
+		short:   shortStr,
+		help:    string(help),
+	}
+	return Flag{setter: setter}
+}
+
+func (f tEnum[T]) shorthand() string {
+	return f.short
+}
+
+func (f tEnum[T]) AddTo(fs *pflag.FlagSet, name string) error {
+	if f.short != "" && !isAlNum(f.short) {
+		return errors.New("flag short name must be an alpha-numeric ASCII character")
+	}
+	if len(f.choices) == 0 {
+		return errors.New("enum must have at least one choice")
+	}
+	if f.tar == nil {
+		return errors.New("target must not be nil")
+	}
+	err := checkFree(fs, name, f.short)
+	if err != nil {
+		return err
+	}
+	*f.tar = f.def
+	val := &enumValue[T]{tar: f.tar, choices: f.choices}
+	fs.VarP(val, name, f.short, f.help)
+	return fs.SetAnnotation(name, annotationChoices, val.names())
+}
+
+// enumValue is [pflag.Value] for enum flags.
+//
+// It's used as a pointer so that the value stays comparable.
+type enumValue[T comparable] struct {
+	tar     *T
+	choices map[string]T
+}
+
+func (v *enumValue[T]) String() string {
+	for _, name := range v.names() {
+		if v.choices[name] == *v.tar {
+			return name
+		}
+	}
+	return fmt.Sprint(*v.tar)
+}
+
+func (v *enumValue[T]) Set(raw string) error {
+	val, found := v.choices[raw]
+	if !found {
+		names := v.names()
+		hint := didYouMean(raw, names)
+		return fmt.Errorf("must be one of: %s%s", strings.Join(names, ", "), hint)
+	}
+	*v.tar = val
+	return nil
+}
+
+func (v *enumValue[T]) Type() string {
+	return "string"
+}
+
+// names returns sorted names of all choices.
+func (v *enumValue[T]) names() []string {
+	names := make([]string, 0, len(v.choices))
+	for name := range v.choices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarning
+)
+
+func TestEnum(t *testing.T) {
+	is := is.New(t)
+
+	var level logLevel
+	levels := map[string]logLevel{
+		"debug":   levelDebug,
+		"info":    levelInfo,
+		"warning": levelWarning,
+		"warn":    levelWarning,
+	}
+	flags := cliff.Flags{
+		"level": cliff.Enum(&level, 'l', levelInfo, levels, "log level"),
+	}
+	parse := func(args ...string) error {
+		return flags.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+
+	is.NoErr(parse())
+	is.Equal(level, levelInfo)
+	is.NoErr(parse("-l", "warn"))
+	is.Equal(level, levelWarning)
+	is.NoErr(parse("--level=debug"))
+	is.Equal(level, levelDebug)
+
+	err := parse("-l", "wran")
+	is.Equal(err.Error(), `invalid argument "wran" for "-l, --level" flag: must be one of: debug, info, warn, warning; did you mean warn?`)
+	err = parse("-l", "error")
+	is.Equal(err.Error(), `invalid argument "error" for "-l, --level" flag: must be one of: debug, info, warn, warning`)
+
+	flags = cliff.Flags{
+		"level": cliff.Enum(&level, 'l', levelInfo, nil, "log level"),
+	}
+	err = parse()
+	is.Equal(err.Error(), "add flag level: enum must have at least one choice")
+}
+
+func TestEnum_ComparableValue(t *testing.T) {
+	is := is.New(t)
+
+	var level logLevel
+	var size int
+	flags := cliff.Flags{
+		"level": cliff.Enum(&level, 'l', levelInfo, map[string]logLevel{"info": levelInfo}, ""),
+		"size":  cliff.FuncFlag(&size, 0, 0, func(string) (int, error) { return 1, nil }, ""),
+	}
+	pfs, err := flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	// Values are used as map keys, which panics for unhashable types.
+	seen := map[any]bool{}
+	seen[pfs.Lookup("level").Value] = true
+	seen[pfs.Lookup("size").Value] = true
+	is.Equal(len(seen), 2)
+}
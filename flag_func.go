@@ -2,7 +2,6 @@ package cliff
 
 import (
 	"errors"
-	"flag"
 
 	"github.com/spf13/pflag"
 )
@@ -36,10 +35,24 @@ func FuncFlag[T any](
 	return Flag{setter: setter}
 }
 
+func (f tFuncFlag[T]) shorthand() string {
+	return f.short
+}
+
 func (f tFuncFlag[T]) AddTo(fs *pflag.FlagSet, name string) error {
 	if f.short != "" && !isAlNum(f.short) {
 		return errors.New("flag short name must be an alpha-numeric ASCII character")
 	}
+	if f.tar == nil {
+		return errors.New("target must not be nil")
+	}
+	if f.parser == nil {
+		return errors.New("parser must not be nil")
+	}
+	err := checkFree(fs, name, f.short)
+	if err != nil {
+		return err
+	}
 
 	patched := func(raw string) error {
 		val, err := f.parser(raw)
@@ -50,12 +63,25 @@ func (f tFuncFlag[T]) AddTo(fs *pflag.FlagSet, name string) error {
 		return nil
 	}
 
-	gfs := flag.NewFlagSet("", flag.ContinueOnError)
-	gfs.Func(name, f.help, patched)
-	goflag := gfs.Lookup(name)
-	pf := pflag.PFlagFromGoFlag(goflag)
-	pf.Name = name
-	pf.Shorthand = f.short
-	fs.AddFlag(pf)
+	fs.VarP(&funcValue{set: patched}, name, f.short, f.help)
 	return nil
 }
+
+// funcValue is [pflag.Value] for [FuncFlag].
+//
+// It's used as a pointer so that the value stays comparable.
+type funcValue struct {
+	set func(string) error
+}
+
+func (f *funcValue) String() string {
+	return ""
+}
+
+func (f *funcValue) Set(val string) error {
+	return f.set(val)
+}
+
+func (f *funcValue) Type() string {
+	return "func"
+}
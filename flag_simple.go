@@ -27,7 +27,7 @@ type Constraint interface {
 		string |
 		time.Duration |
 		uint | uint16 | uint32 | uint64 | uint8 |
-		Count | BytesHex | BytesBase64
+		Count | BytesHex | BytesBase64 | Path
 }
 
 // Short is a literal character representing shortcut for a flag.
@@ -46,6 +46,11 @@ type BytesHex []byte
 // BytesBase64 is a slice of bytes represented in CLI as a base64-encoded string.
 type BytesBase64 []byte
 
+// Path is a string representing a file system path.
+//
+// It's the same as string but shells complete it as a file path.
+type Path string
+
 // tPFlag represents all info about a CLI flag except its name.
 type tPFlag struct {
 	tar   any    // target where to put the parsed result
@@ -55,25 +60,52 @@ type tPFlag struct {
 }
 
 // F creates a new flag.
-func F[T Constraint](val *T, short Short, def T, help Help) Flag {
+//
+// The validators are called for the final value of the flag after parsing.
+// They are not called if the flag is not passed and keeps the default value.
+func F[T Constraint](val *T, short Short, def T, help Help, validators ...func(T) error) Flag {
 	shortStr := ""
 	if short != 0 {
 		shortStr = string(short)
 	}
 	setter := tPFlag{
-		tar:   val,
+		tar:   target(val),
 		def:   def,
 		short: shortStr,
 		help:  string(help),
 	}
-	return Flag{setter: setter}
+	return Flag{setter: setter, check: validateAll(val, validators)}
+}
+
+// R creates a new required flag.
+//
+// It's the same as [F] with the zero default value and [Flag.Required].
+func R[T Constraint](val *T, short Short, help Help, validators ...func(T) error) Flag {
+	var def T
+	return F(val, short, def, help, validators...).Required()
+}
+
+// target converts a nil pointer into untyped nil so that it can be detected in [tPFlag].
+func target[T any](val *T) any {
+	if val == nil {
+		return nil
+	}
+	return val
+}
+
+func (f tPFlag) shorthand() string {
+	return f.short
 }
 
 func (f tPFlag) AddTo(fs *pflag.FlagSet, name string) error {
 	if f.short != "" && !isAlNum(f.short) {
 		return errors.New("flag short name must be an alpha-numeric ASCII character")
 	}
-	err := f.pflagAddFlag(name, fs)
+	err := checkFree(fs, name, f.short)
+	if err != nil {
+		return err
+	}
+	err = f.pflagAddFlag(name, fs)
 	if err != nil {
 		return err
 	}
@@ -81,6 +113,9 @@ func (f tPFlag) AddTo(fs *pflag.FlagSet, name string) error {
 }
 
 func (f tPFlag) pflagAddFlag(name string, fs *pflag.FlagSet) error {
+	if f.tar == nil {
+		return errors.New("target must not be nil")
+	}
 	switch def := any(f.def).(type) {
 	case []bool:
 		v := any(f.tar).(*[]bool)
@@ -172,6 +207,10 @@ func (f tPFlag) pflagAddFlag(name string, fs *pflag.FlagSet) error {
 	case string:
 		v := any(f.tar).(*string)
 		fs.StringVarP(v, name, f.short, def, f.help)
+	case Path:
+		v := any(f.tar).(*Path)
+		*v = def
+		fs.VarP((*pathValue)(v), name, f.short, f.help)
 	case uint16:
 		v := any(f.tar).(*uint16)
 		fs.Uint16VarP(v, name, f.short, def, f.help)
@@ -195,3 +234,19 @@ func (f tPFlag) pflagAddFlag(name string, fs *pflag.FlagSet) error {
 	}
 	return nil
 }
+
+// pathValue is [pflag.Value] for [Path].
+type pathValue Path
+
+func (p *pathValue) String() string {
+	return string(*p)
+}
+
+func (p *pathValue) Set(val string) error {
+	*p = pathValue(val)
+	return nil
+}
+
+func (p *pathValue) Type() string {
+	return "path"
+}
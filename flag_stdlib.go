@@ -26,10 +26,21 @@ func GoFlag(short Short, flag *flag.Flag) Flag {
 	return Flag{setter: setter}
 }
 
+func (f tGoFlag) shorthand() string {
+	return f.short
+}
+
 func (f tGoFlag) AddTo(fs *pflag.FlagSet, name string) error {
 	if f.short != "" && !isAlNum(f.short) {
 		return errors.New("flag short name must be an alpha-numeric ASCII character")
 	}
+	if f.flag == nil || f.flag.Value == nil {
+		return errors.New("go flag must not be nil")
+	}
+	err := checkFree(fs, name, f.short)
+	if err != nil {
+		return err
+	}
 	pf := pflag.PFlagFromGoFlag(f.flag)
 	pf.Name = name
 	pf.Shorthand = f.short
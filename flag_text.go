@@ -0,0 +1,102 @@
+package cliff
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// tText represents all info about a CLI flag for a [encoding.TextUnmarshaler] except its name.
+type tText[T any, P textUnmarshaler[T]] struct {
+	tar   *T
+	def   T
+	short string // short alias for the flag
+	help  string // usage message
+}
+
+// textUnmarshaler is a pointer to T that implements [encoding.TextUnmarshaler].
+type textUnmarshaler[T any] interface {
+	*T
+	encoding.TextUnmarshaler
+}
+
+// TextFlag creates a new flag for a type which pointer implements [encoding.TextUnmarshaler].
+//
+// It works with types like [net/netip.Addr] or [log/slog.Level] without writing a parser.
+// If the type also implements [encoding.TextMarshaler], it's used to show the default value in help.
+// The name of the type is shown in help as the type of the flag value.
+//
+// The validators are called for the final value of the flag after parsing.
+// They are not called if the flag is not passed and keeps the default value.
+func TextFlag[T any, P textUnmarshaler[T]](
+	tar *T,
+	short Short,
+	def T,
+	help Help,
+	validators ...func(T) error,
+) Flag {
+	shortStr := ""
+	if short != 0 {
+		shortStr = string(short)
+	}
+	setter := tText[T, P]{
+		tar:   tar,
+		def:   def,
+		short: shortStr,
+		help:  string(help),
+	}
+	return Flag{setter: setter, check: validateAll(tar, validators)}
+}
+
+func (f tText[T, P]) shorthand() string {
+	return f.short
+}
+
+func (f tText[T, P]) AddTo(fs *pflag.FlagSet, name string) error {
+	if f.short != "" && !isAlNum(f.short) {
+		return errors.New("flag short name must be an alpha-numeric ASCII character")
+	}
+	if f.tar == nil {
+		return errors.New("target must not be nil")
+	}
+	err := checkFree(fs, name, f.short)
+	if err != nil {
+		return err
+	}
+	*f.tar = f.def
+	fs.VarP(textValue[T, P]{tar: f.tar}, name, f.short, f.help)
+	return nil
+}
+
+// textValue is [pflag.Value] for [encoding.TextUnmarshaler].
+type textValue[T any, P textUnmarshaler[T]] struct {
+	tar *T
+}
+
+func (v textValue[T, P]) String() string {
+	m, ok := any(v.tar).(encoding.TextMarshaler)
+	if !ok {
+		return fmt.Sprint(*v.tar)
+	}
+	text, err := m.MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+func (v textValue[T, P]) Set(val string) error {
+	return P(v.tar).UnmarshalText([]byte(val))
+}
+
+func (v textValue[T, P]) Type() string {
+	name := reflect.TypeOf(v.tar).Elem().Name()
+	if name == "" {
+		return "value"
+	}
+	return strings.ToLower(name)
+}
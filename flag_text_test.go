@@ -0,0 +1,92 @@
+package cliff_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+// level implements only encoding.TextUnmarshaler.
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 1
+	case "info":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+func TestTextFlag(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		addr  netip.Addr
+		level level
+	}
+	init := func(c *Config) cliff.Flags {
+		return cliff.Flags{
+			"addr":  cliff.TextFlag(&c.addr, 'a', netip.MustParseAddr("127.0.0.1"), "address to listen on"),
+			"level": cliff.TextFlag(&c.level, 0, 2, "log level"),
+		}
+	}
+	parse := func(args ...string) (Config, error) {
+		return cliff.Parse(io.Discard, append([]string{"example"}, args...), init)
+	}
+
+	c, err := parse()
+	is.NoErr(err)
+	is.Equal(c.addr, netip.MustParseAddr("127.0.0.1"))
+	is.Equal(c.level, level(2))
+
+	c, err = parse("-a", "::1", "--level", "DEBUG")
+	is.NoErr(err)
+	is.Equal(c.addr, netip.MustParseAddr("::1"))
+	is.Equal(c.level, level(1))
+
+	_, err = parse("--addr", "localhost")
+	var valErr cliff.InvalidValueError
+	is.True(errors.As(err, &valErr))
+	is.Equal(valErr.Name, "addr")
+	is.Equal(valErr.Value, "localhost")
+
+	var help bytes.Buffer
+	_, _ = cliff.Parse(&help, []string{"example", "--help"}, init)
+	is.Equal(help.String(), "Usage: example [flags]\n\nFlags:\n"+
+		"  -a, --addr addr     address to listen on (default 127.0.0.1)\n"+
+		"      --level level   log level (default 2)\n")
+}
+
+func TestTextFlag_Validators(t *testing.T) {
+	is := is.New(t)
+
+	var addr netip.Addr
+	isV4 := func(a netip.Addr) error {
+		if !a.Is4() {
+			return errors.New("must be IPv4")
+		}
+		return nil
+	}
+	flags := cliff.Flags{"addr": cliff.TextFlag(&addr, 0, netip.Addr{}, "", isV4)}
+	is.NoErr(flags.Parse(io.Discard, []string{"example", "--addr", "10.0.0.1"}))
+	err := flags.Parse(io.Discard, []string{"example", "--addr", "::1"})
+	is.Equal(err.Error(), "invalid value for --addr: must be IPv4")
+}
+
+func TestTextFlag_Errors(t *testing.T) {
+	is := is.New(t)
+
+	flags := cliff.Flags{"addr": cliff.TextFlag((*netip.Addr)(nil), 0, netip.Addr{}, "")}
+	_, err := flags.PFlagSet(io.Discard, "example")
+	is.Equal(err.Error(), "add flag addr: target must not be nil")
+}
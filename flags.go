@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -17,29 +18,35 @@ var isValidFlag = regexp.MustCompile(`^[a-zA-Z0-9-]+$`).MatchString
 
 // MustParse parses CLI flags, writes errors and warnings into stderr and exits on error or help.
 //
+// If init returns [Spec], its ExitCodes are used.
+//
 // Typical usage:
 //
 //	cliff.MustParse(os.Stderr, os.Exit, os.Args, flags)
-func MustParse[T any](
+func MustParse[T any, D Definition](
 	stderr io.Writer,
 	exit func(int),
 	args []string,
-	init func(c *T) Flags,
+	init func(c *T) D,
 ) T {
-	config, err := Parse[T](stderr, args, init)
-	HandleError(stderr, exit, err)
+	var config T
+	spec := toSpec(init(&config))
+	err := spec.Parse(stderr, args)
+	spec.ExitCodes.HandleError(stderr, exit, err)
 	return config
 }
 
-// MustParse parses CLI flags, writes warnings into stderr and returns error on error or help.
+// Parse parses CLI flags, writes warnings into stderr and returns error on error or help.
+//
+// The init function can return either [Flags] or [Spec].
 //
 // Typical usage:
 //
 //	cliff.Parse(os.Stderr, os.Args, flags)
-func Parse[T any](stderr io.Writer, args []string, init func(c *T) Flags) (T, error) {
+func Parse[T any, D Definition](stderr io.Writer, args []string, init func(c *T) D) (T, error) {
 	var config T
-	flags := init(&config)
-	err := flags.Parse(stderr, args)
+	def := init(&config)
+	err := toSpec(def).Parse(stderr, args)
 	return config, err
 }
 
@@ -54,45 +61,89 @@ type Flags map[string]Flag
 //
 //	flags.Parse(os.Stderr, os.Args)
 func (fs Flags) Parse(stderr io.Writer, args []string) error {
-	pfs, err := fs.PFlagSet(stderr, args[0])
-	if err != nil {
-		return err
-	}
-	return pfs.Parse(args[1:])
+	return Spec{Flags: fs}.Parse(stderr, args)
 }
 
+// FlagSet returns a stdlib [flag.FlagSet] populated with defined flags.
+//
+// Shorthands are added as separate flags sharing the value with the long name.
 func (fs Flags) FlagSet(stderr io.Writer, name string) (*flag.FlagSet, error) {
 	pfs, err := fs.PFlagSet(stderr, name)
 	if err != nil {
 		return nil, err
 	}
 	gfs := flag.NewFlagSet(name, flag.ContinueOnError)
+	// In stdlib flags, shorthands share the namespace with long names,
+	// and [flag.FlagSet.Var] panics on redefinition.
+	owners := make(map[string]string)
+	conflicts := make([]string, 0)
+	add := func(pf *pflag.Flag, name, owner string) {
+		other, found := owners[name]
+		if found {
+			conflicts = append(conflicts, fmt.Sprintf("-%s is used by %s and %s", name, other, owner))
+			return
+		}
+		owners[name] = owner
+		gfs.Var(pf.Value, name, pf.Usage)
+	}
 	pfs.VisitAll(func(pf *pflag.Flag) {
-		gfs.Var(pf.Value, pf.Name, pf.Usage)
+		add(pf, pf.Name, flagOwner(pf))
 		if pf.Shorthand != "" {
-			gfs.Var(pf.Value, pf.Shorthand, pf.Usage)
+			add(pf, pf.Shorthand, "shorthand of --"+pf.Name)
 		}
 	})
+	if len(conflicts) != 0 {
+		return nil, DefinitionError{Err: fmt.Errorf("conflicting flags: %s", strings.Join(conflicts, "; "))}
+	}
 	return gfs, nil
 }
 
 // PFlagSet returns a [pflag.FlagSet] populated with defined flags.
+//
+// The usage function of the flag set writes the help using [DefaultHelp].
+// If flags conflict with each other, all conflicts are reported in a single [DefinitionError].
 func (fs Flags) PFlagSet(stderr io.Writer, name string) (*pflag.FlagSet, error) {
 	pfs := pflag.NewFlagSet(name, pflag.ContinueOnError)
 	pfs.SetOutput(stderr)
-	for name, flag := range fs {
+	pfs.Usage = func() {
+		writeHelp(stderr, name, Spec{Flags: fs}, nil, pfs, nil)
+	}
+	for _, name := range fs.names() {
 		err := validateName(name)
 		if err != nil {
-			return nil, fmt.Errorf("validate flag name (%s): %v", name, err)
+			return nil, DefinitionError{Err: fmt.Errorf("validate flag name (%s): %v", name, err)}
 		}
+		for _, alias := range fs[name].aliases {
+			err = validateName(alias)
+			if err != nil {
+				return nil, DefinitionError{Err: fmt.Errorf("validate alias (%s) of flag %s: %v", alias, name, err)}
+			}
+		}
+	}
+	err := fs.CheckConflicts(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range fs.names() {
+		flag := fs[name]
 		err = flag.AddTo(pfs, name)
 		if err != nil {
-			return nil, fmt.Errorf("add flag %s: %v", name, err)
+			return nil, DefinitionError{Err: fmt.Errorf("add flag %s: %v", name, err)}
 		}
 	}
 	return pfs, nil
 }
 
+// names returns sorted names of all flags.
+func (fs Flags) names() []string {
+	names := make([]string, 0, len(fs))
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func validateName(name string) error {
 	if name == "" {
 		return errors.New("must not be empty")
@@ -116,13 +167,9 @@ func validateName(name string) error {
 }
 
 // HandleError interrupts the program if an error occurred when parsing arguments.
+//
+// It uses the default [ExitCodes]: 0 for help and version,
+// 70 for [DefinitionError], and 2 for all other errors.
 func HandleError(stderr io.Writer, exit func(int), err error) {
-	if err == nil {
-		return
-	}
-	if err == pflag.ErrHelp || err == flag.ErrHelp {
-		exit(0)
-	}
-	fmt.Fprintln(stderr, err)
-	exit(2)
+	ExitCodes{}.HandleError(stderr, exit, err)
 }
@@ -0,0 +1,141 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+type groupKind int
+
+const (
+	groupExactlyOne groupKind = iota
+	groupAtMostOne
+	groupAllOrNone
+	groupRequires
+)
+
+// Group is a constraint on which flags can be passed together.
+type Group struct {
+	kind  groupKind
+	names []string
+}
+
+// ExactlyOne creates a group of flags in which exactly one flag must be set.
+func ExactlyOne(names ...string) Group {
+	return Group{kind: groupExactlyOne, names: names}
+}
+
+// AtMostOne creates a group of mutually exclusive flags.
+//
+// It's fine if none of the flags is set.
+func AtMostOne(names ...string) Group {
+	return Group{kind: groupAtMostOne, names: names}
+}
+
+// AllOrNone creates a group of flags that must be either all set or all not set.
+func AllOrNone(names ...string) Group {
+	return Group{kind: groupAllOrNone, names: names}
+}
+
+// Requires creates a group in which, if the first flag is set, all the other flags must be set too.
+func Requires(name string, deps ...string) Group {
+	return Group{kind: groupRequires, names: append([]string{name}, deps...)}
+}
+
+// String returns the human-readable description of the group.
+func (g Group) String() string {
+	names := dashed(g.names)
+	switch g.kind {
+	case groupExactlyOne:
+		return "exactly one of " + strings.Join(names, ", ")
+	case groupAtMostOne:
+		return "at most one of " + strings.Join(names, ", ")
+	case groupAllOrNone:
+		return "all or none of " + strings.Join(names, ", ")
+	case groupRequires:
+		return names[0] + " requires " + strings.Join(names[1:], ", ")
+	}
+	return ""
+}
+
+// resolvedGroup is a [Group] with flag names resolved into flags.
+type resolvedGroup struct {
+	group Group
+	flags []*pflag.Flag
+}
+
+// resolveGroups finds all flags mentioned in the groups.
+func resolveGroups(pfs *pflag.FlagSet, groups []Group) ([]resolvedGroup, error) {
+	result := make([]resolvedGroup, 0, len(groups))
+	for _, g := range groups {
+		if len(g.names) < 2 {
+			return nil, errors.New("flag group must have at least two flags")
+		}
+		flags := make([]*pflag.Flag, len(g.names))
+		for i, name := range g.names {
+			flags[i] = pfs.Lookup(name)
+			if flags[i] == nil {
+				return nil, fmt.Errorf("flag group refers to unknown flag: %s", name)
+			}
+		}
+		result = append(result, resolvedGroup{group: g, flags: flags})
+	}
+	return result, nil
+}
+
+// check returns an error if the group constraint is violated.
+//
+// The set contains flags set from env vars or config files.
+func (g resolvedGroup) check(set map[*pflag.Flag]Source) error {
+	count := 0
+	for _, pf := range g.flags {
+		if flagSource(pf, set) != SourceDefault {
+			count++
+		}
+	}
+	names := strings.Join(dashed(g.group.names), ", ")
+	switch g.group.kind {
+	case groupExactlyOne:
+		if count != 1 {
+			return fmt.Errorf("exactly one of the flags must be set: %s", names)
+		}
+	case groupAtMostOne:
+		if count > 1 {
+			return fmt.Errorf("at most one of the flags can be set: %s", names)
+		}
+	case groupAllOrNone:
+		if count != 0 && count != len(g.flags) {
+			return fmt.Errorf("either all or none of the flags must be set: %s", names)
+		}
+	case groupRequires:
+		first := g.flags[0]
+		if flagSource(first, set) != SourceDefault && count != len(g.flags) {
+			deps := strings.Join(dashed(g.group.names[1:]), ", ")
+			return fmt.Errorf("flag --%s requires %s", first.Name, deps)
+		}
+	}
+	return nil
+}
+
+// uses checks if any of the given flags is a part of the group.
+func (g resolvedGroup) uses(flags []*pflag.Flag) bool {
+	used := toSet(flags)
+	for _, pf := range g.flags {
+		if used[pf] {
+			return true
+		}
+	}
+	return false
+}
+
+// dashed adds "--" before each flag name.
+func dashed(names []string) []string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = "--" + name
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestGroups(t *testing.T) {
+	is := is.New(t)
+
+	var a, b, c, d, e, f bool
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"a": cliff.F(&a, 0, false, ""),
+			"b": cliff.F(&b, 0, false, ""),
+			"c": cliff.F(&c, 0, false, ""),
+			"d": cliff.F(&d, 0, false, ""),
+			"e": cliff.F(&e, 0, false, "").Env("E"),
+			"f": cliff.F(&f, 0, false, ""),
+		},
+		Groups: []cliff.Group{
+			cliff.ExactlyOne("a", "b"),
+			cliff.AtMostOne("b", "c"),
+			cliff.AllOrNone("d", "e"),
+			cliff.Requires("f", "a", "d"),
+		},
+		LookupEnv: func(key string) (string, bool) { return "", false },
+	}
+	parse := func(args ...string) error {
+		return spec.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+
+	is.NoErr(parse("--a"))
+	is.NoErr(parse("--b"))
+	is.NoErr(parse("--a", "--c"))
+	is.NoErr(parse("--a", "--d", "--e"))
+	is.NoErr(parse("--a", "--d", "--e", "--f"))
+
+	is.Equal(parse().Error(), "exactly one of the flags must be set: --a, --b")
+	is.Equal(parse("--a", "--b").Error(), "exactly one of the flags must be set: --a, --b")
+	is.Equal(parse("--b", "--c").Error(), "at most one of the flags can be set: --b, --c")
+	is.Equal(parse("--a", "--d").Error(), "either all or none of the flags must be set: --d, --e")
+	is.Equal(parse("--b", "--f").Error(), "flag --f requires --a, --d")
+
+	// Flags set from env vars count as set.
+	spec.LookupEnv = func(key string) (string, bool) { return "true", true }
+	is.NoErr(parse("--a", "--d"))
+}
+
+func TestGroups_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var a bool
+	spec := cliff.Spec{
+		Flags:  cliff.Flags{"a": cliff.F(&a, 0, false, "")},
+		Groups: []cliff.Group{cliff.AtMostOne("a", "b")},
+	}
+	err := spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "flag group refers to unknown flag: b")
+
+	spec.Groups = []cliff.Group{cliff.AtMostOne("a")}
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "flag group must have at least two flags")
+}
+
+func TestGroups_Persistent(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct{ token, tokenFile string }
+	noop := func(Config) error { return nil }
+	root := cliff.Cmd(func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"token":      cliff.F(&c.token, 0, "", "").Persistent(),
+				"token-file": cliff.F(&c.tokenFile, 0, "", "").Persistent(),
+			},
+			Groups: []cliff.Group{cliff.ExactlyOne("token", "token-file")},
+			Commands: cliff.Commands{
+				"sub": cliff.Cmd(func(*Config) cliff.Flags { return nil }, noop, ""),
+			},
+		}
+	}, noop, "")
+	run := func(args ...string) error {
+		return cliff.Run(io.Discard, append([]string{"example"}, args...), root)
+	}
+	is.NoErr(run("sub", "--token", "x"))
+	is.NoErr(run("--token-file", "x", "sub"))
+	is.Equal(run("sub").Error(), "exactly one of the flags must be set: --token, --token-file")
+	is.Equal(run("--token", "x", "sub", "--token-file", "y").Error(), "exactly one of the flags must be set: --token, --token-file")
+}
@@ -0,0 +1,518 @@
+package cliff
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/pflag"
+)
+
+// HelpRenderer writes the help message for the given [HelpPage].
+//
+// Set it as [Spec.HelpRenderer] to customize the help shown on --help.
+// See [DefaultHelp], [HelpFunc], and [HelpTemplate].
+type HelpRenderer interface {
+	RenderHelp(w io.Writer, page HelpPage) error
+}
+
+// HelpFunc is a function implementing [HelpRenderer].
+type HelpFunc func(w io.Writer, page HelpPage) error
+
+// RenderHelp implements [HelpRenderer].
+func (f HelpFunc) RenderHelp(w io.Writer, page HelpPage) error {
+	return f(w, page)
+}
+
+// HelpTemplate creates a [HelpRenderer] executing the given template with [HelpPage].
+//
+// Typical usage:
+//
+//	tmpl := template.Must(template.New("help").Parse("Usage: {{.Usage}}\n"))
+//	spec.HelpRenderer = cliff.HelpTemplate(tmpl)
+func HelpTemplate(tmpl *template.Template) HelpRenderer {
+	return HelpFunc(func(w io.Writer, page HelpPage) error {
+		return tmpl.Execute(w, page)
+	})
+}
+
+// HelpPage is everything shown in the help message.
+type HelpPage struct {
+	// Name is the program name followed by the subcommand names.
+	Name string
+
+	// Usage is the usage line, like "example [flags] SRC".
+	Usage string
+
+	// Description is the description of the program, see [Spec.Description].
+	Description string
+
+	// Commands are subcommands sorted by name.
+	Commands []HelpCommand
+
+	// Args are positional arguments in the order they are expected.
+	Args []HelpArg
+
+	// Sections are titled lists of visible flags, see [Spec.Sections].
+	Sections []HelpSection
+
+	// Groups are descriptions of flag groups, like "exactly one of --a, --b".
+	Groups []string
+
+	// Examples are usage examples, see [Spec.Examples].
+	Examples []Example
+
+	// Epilogue is the text shown at the end, see [Spec.Epilogue].
+	Epilogue string
+
+	// Width is the maximum line width.
+	//
+	// It's the terminal width, the value of COLUMNS env var, or 80.
+	Width int
+
+	// Color is true if ANSI colors should be used, see [Spec.Color].
+	Color bool
+}
+
+// HelpCommand is a subcommand shown in help.
+type HelpCommand struct {
+	Name string
+	Help string
+}
+
+// HelpArg is a positional argument shown in help.
+type HelpArg struct {
+	Name    string // the name in uppercase, like "SRC" or "FILES..."
+	Help    string
+	Default string // empty if the default is the zero value
+}
+
+// HelpSection is a titled list of flags shown in help.
+type HelpSection struct {
+	Title string
+	Flags []HelpFlag
+}
+
+// HelpFlag is a flag shown in help.
+type HelpFlag struct {
+	Name      string
+	Shorthand string // empty if there is no shorthand or it is deprecated
+	Type      string // the value name, like "int", empty for boolean flags
+	Help      string
+	Default   string // empty if the default is the zero value
+	Env       string // the env var to read the value from
+	Required  bool
+	Choices   []string
+
+	// Names is the left column of the default help, like "-p, --port int".
+	Names string
+
+	// Usage is the right column of the default help:
+	// help, choices, default, env var, and if it's required.
+	Usage string
+}
+
+// Example is a usage example shown in help.
+type Example struct {
+	Command string // the full command, like "example --port 8080"
+	Help    string // what the command does
+}
+
+// DefaultHelp is the default [HelpRenderer] for [Spec].
+//
+// Wrap it into [HelpFunc] to use it as a renderer
+// or call it from a custom renderer to extend the default help.
+func DefaultHelp(w io.Writer, page HelpPage) error {
+	fmt.Fprintf(w, "Usage: %s\n", page.Usage)
+
+	if page.Description != "" {
+		fmt.Fprintf(w, "\n%s\n", wrapText(page.Description, page.Width))
+	}
+
+	if len(page.Commands) != 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		rows := make([]helpRow, len(page.Commands))
+		for i, c := range page.Commands {
+			rows[i] = helpRow{left: "  " + c.Name, right: c.Help}
+		}
+		writeColumns(w, rows, page.Width)
+	}
+
+	if len(page.Args) != 0 {
+		fmt.Fprintln(w, "\nArguments:")
+		rows := make([]helpRow, len(page.Args))
+		for i, a := range page.Args {
+			usage := a.Help
+			if a.Default != "" {
+				usage += fmt.Sprintf(" (default %s)", colorize(a.Default, ansiYellow, page.Color))
+			}
+			rows[i] = helpRow{left: "  " + a.Name, right: usage}
+		}
+		writeColumns(w, rows, page.Width)
+	}
+
+	for _, sec := range page.Sections {
+		fmt.Fprintf(w, "\n%s:\n", sec.Title)
+		writeFlags(w, sec.Flags, page.Width, page.Color)
+	}
+
+	if len(page.Groups) != 0 {
+		fmt.Fprintln(w, "\nFlag groups:")
+		for _, g := range page.Groups {
+			fmt.Fprintf(w, "  %s\n", g)
+		}
+	}
+
+	if len(page.Examples) != 0 {
+		fmt.Fprintln(w, "\nExamples:")
+		for i, e := range page.Examples {
+			if i != 0 {
+				fmt.Fprintln(w)
+			}
+			if e.Help != "" {
+				fmt.Fprintf(w, "  # %s\n", e.Help)
+			}
+			fmt.Fprintf(w, "  %s\n", e.Command)
+		}
+	}
+
+	if page.Epilogue != "" {
+		fmt.Fprintf(w, "\n%s\n", wrapText(page.Epilogue, page.Width))
+	}
+	return nil
+}
+
+// writeHelp writes the help message for the CLI into the given stream.
+//
+// The vals are positional argument values as returned by [Args.values].
+// They are used to show the default values.
+// The inherited flags are persistent flags of the parent commands.
+func writeHelp(w io.Writer, name string, spec Spec, vals []pflag.Value, pfs *pflag.FlagSet, inherited []*pflag.Flag) {
+	page := helpPage(name, spec, vals, pfs, inherited)
+	page.Width = helpWidth(w, spec.lookupEnv())
+	page.Color = spec.Color && useColor(w, spec.lookupEnv())
+	renderer := spec.HelpRenderer
+	if renderer == nil {
+		renderer = HelpFunc(DefaultHelp)
+	}
+	err := renderer.RenderHelp(w, page)
+	if err != nil {
+		fmt.Fprintf(w, "render help: %v\n", err)
+	}
+}
+
+// helpPage collects everything shown in help.
+func helpPage(name string, spec Spec, vals []pflag.Value, pfs *pflag.FlagSet, inherited []*pflag.Flag) HelpPage {
+	page := HelpPage{
+		Name:        name,
+		Usage:       spec.Usage,
+		Description: spec.Description,
+		Examples:    spec.Examples,
+		Epilogue:    spec.Epilogue,
+	}
+	if page.Usage == "" {
+		page.Usage = usageLine(name, spec, pfs)
+	}
+	for _, name := range spec.Commands.names() {
+		page.Commands = append(page.Commands, HelpCommand{Name: name, Help: spec.Commands[name].help})
+	}
+	for i, a := range spec.Args {
+		arg := HelpArg{Name: a.metavar(), Help: a.setter.help}
+		def := vals[i].String()
+		if !isZeroDefault(def) {
+			arg.Default = def
+		}
+		page.Args = append(page.Args, arg)
+	}
+	for _, sec := range flagSections(pfs, spec.Sections, inherited) {
+		hs := HelpSection{Title: sec.title}
+		for _, pf := range sec.flags {
+			hs.Flags = append(hs.Flags, helpFlag(pf))
+		}
+		page.Sections = append(page.Sections, hs)
+	}
+	for _, g := range spec.Groups {
+		page.Groups = append(page.Groups, g.String())
+	}
+	return page
+}
+
+// usageLine generates the usage line, like "example [flags] SRC [DST]".
+func usageLine(name string, spec Spec, pfs *pflag.FlagSet) string {
+	line := name
+	if pfs.HasAvailableFlags() {
+		line += " [flags]"
+	}
+	for _, a := range spec.Args {
+		if a.required {
+			line += " " + a.metavar()
+		} else {
+			line += " [" + a.metavar() + "]"
+		}
+	}
+	if len(spec.Commands) != 0 {
+		line += " COMMAND"
+	}
+	return line
+}
+
+// helpFlag collects everything about the flag shown in help.
+func helpFlag(pf *pflag.Flag) HelpFlag {
+	varname, usage := pflag.UnquoteUsage(pf)
+	hf := HelpFlag{
+		Name:     pf.Name,
+		Type:     varname,
+		Help:     usage,
+		Env:      getEnv(pf),
+		Required: isRequired(pf),
+		Choices:  pf.Annotations[annotationChoices],
+		Names:    strings.TrimLeft(flagUsageName(pf), " "),
+		Usage:    flagUsage(pf),
+	}
+	if pf.ShorthandDeprecated == "" {
+		hf.Shorthand = pf.Shorthand
+	}
+	if !isZeroFlagDefault(pf) {
+		hf.Default = pf.DefValue
+	}
+	return hf
+}
+
+// writeFlags writes the aligned list of the given flags.
+//
+// The format is the same as of [pflag.FlagSet.FlagUsages]
+// but it also includes env vars and other cliff-specific info.
+func writeFlags(w io.Writer, flags []HelpFlag, width int, color bool) {
+	rows := make([]helpRow, len(flags))
+	for i, f := range flags {
+		indent := "      "
+		if f.Shorthand != "" {
+			indent = "  "
+		}
+		rows[i] = helpRow{
+			left:  indent + f.Names,
+			right: f.Usage,
+		}
+		if color {
+			rows[i].left = indent + colorize(f.Names, ansiBold, true)
+			rows[i].right = colorizeDefault(f)
+		}
+		rows[i].right = glueDefault(rows[i].right, f, color)
+	}
+	writeColumns(w, rows, width)
+}
+
+// glueDefault joins words of the "(default ...)" annotation in the flag usage
+// with [glue], so that the annotation isn't split when wrapping.
+func glueDefault(usage string, f HelpFlag, color bool) string {
+	if f.Default == "" || !strings.HasPrefix(usage, f.Help) {
+		return usage
+	}
+	for _, def := range []string{strconv.Quote(f.Default), f.Default} {
+		part := "(default " + colorize(def, ansiYellow, color) + ")"
+		i := strings.Index(usage[len(f.Help):], part)
+		if i != -1 {
+			i += len(f.Help)
+			return usage[:i] + strings.ReplaceAll(part, " ", glue) + usage[i+len(part):]
+		}
+	}
+	return usage
+}
+
+// colorizeDefault returns the flag usage with the default value highlighted.
+func colorizeDefault(f HelpFlag) string {
+	if f.Default == "" {
+		return f.Usage
+	}
+	for _, def := range []string{strconv.Quote(f.Default), f.Default} {
+		// The usage starts with the help, the default is always after it.
+		part := " (default " + def + ")"
+		i := strings.Index(f.Usage[len(f.Help):], part)
+		if i != -1 {
+			i += len(f.Help) + len(" (default ")
+			return f.Usage[:i] + colorize(def, ansiYellow, true) + f.Usage[i+len(def):]
+		}
+	}
+	return f.Usage
+}
+
+// flagUsageName returns the left column of the flag help: names and the value type.
+func flagUsageName(pf *pflag.Flag) string {
+	line := ""
+	if pf.Shorthand != "" && pf.ShorthandDeprecated == "" {
+		line = fmt.Sprintf("  -%s, %s", pf.Shorthand, longName(pf))
+	} else {
+		line = fmt.Sprintf("      %s", longName(pf))
+	}
+	varname, _ := pflag.UnquoteUsage(pf)
+	if varname != "" {
+		line += " " + varname
+	}
+	if pf.NoOptDefVal != "" {
+		switch pf.Value.Type() {
+		case "string":
+			line += fmt.Sprintf("[=%q]", pf.NoOptDefVal)
+		case "bool":
+			if pf.NoOptDefVal != "true" {
+				line += fmt.Sprintf("[=%s]", pf.NoOptDefVal)
+			}
+		case "count":
+			if pf.NoOptDefVal != "+1" {
+				line += fmt.Sprintf("[=%s]", pf.NoOptDefVal)
+			}
+		default:
+			line += fmt.Sprintf("[=%s]", pf.NoOptDefVal)
+		}
+	}
+	return line
+}
+
+// flagUsage returns the right column of the flag help: description, default, env var.
+func flagUsage(pf *pflag.Flag) string {
+	_, usage := pflag.UnquoteUsage(pf)
+	choices := pf.Annotations[annotationChoices]
+	if len(choices) != 0 {
+		usage += fmt.Sprintf(" (one of: %s)", strings.Join(choices, ", "))
+	}
+	if !isZeroFlagDefault(pf) {
+		if pf.Value.Type() == "string" || pf.Value.Type() == "path" {
+			usage += fmt.Sprintf(" (default %q)", pf.DefValue)
+		} else {
+			usage += fmt.Sprintf(" (default %s)", pf.DefValue)
+		}
+	}
+	env := getEnv(pf)
+	if env != "" {
+		usage += fmt.Sprintf(" [$%s]", env)
+	}
+	if isRequired(pf) {
+		usage += " (required)"
+	}
+	return usage
+}
+
+// isZeroFlagDefault checks if the default value of the flag doesn't need to be shown in help.
+func isZeroFlagDefault(pf *pflag.Flag) bool {
+	if pf.Value.Type() == "string" || pf.Value.Type() == "path" {
+		return pf.DefValue == ""
+	}
+	return isZeroDefault(pf.DefValue)
+}
+
+// isZeroDefault checks if the given default value doesn't need to be shown in help.
+func isZeroDefault(def string) bool {
+	switch def {
+	case "", "0", "0s", "false", "[]", "<nil>":
+		return true
+	}
+	return false
+}
+
+const (
+	ansiBold   = "\x1b[1m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// colorize wraps the text into the given ANSI style if colors are enabled.
+func colorize(s, style string, color bool) string {
+	if !color || s == "" {
+		return s
+	}
+	return style + s + ansiReset
+}
+
+// helpRow is a line of a two-column list in help.
+type helpRow struct {
+	left  string
+	right string
+}
+
+// columnGap is the number of spaces between columns.
+const columnGap = 3
+
+// minRightWidth is the minimum width of the right column when wrapping.
+//
+// If the terminal is too narrow, lines will be longer than the terminal width.
+const minRightWidth = 20
+
+// writeColumns writes aligned rows, wrapping the right column to fit the width.
+func writeColumns(w io.Writer, rows []helpRow, width int) {
+	leftWidth := 0
+	for _, r := range rows {
+		leftWidth = maxInt(leftWidth, visibleLen(r.left))
+	}
+	leftWidth += columnGap
+	rightWidth := maxInt(width-leftWidth, minRightWidth)
+	for _, r := range rows {
+		pad := strings.Repeat(" ", leftWidth-visibleLen(r.left))
+		lines := strings.Split(wrapText(r.right, rightWidth), "\n")
+		fmt.Fprintf(w, "%s%s%s\n", r.left, pad, lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(w, "%s%s\n", strings.Repeat(" ", leftWidth), line)
+		}
+	}
+}
+
+// glue joins words that must not be split by [wrapText].
+//
+// It's replaced by a space after wrapping.
+const glue = "\x00"
+
+// wrapText wraps each line of the text on spaces to fit into the given width.
+//
+// Words longer than the width are not split.
+func wrapText(text string, width int) string {
+	if width <= 0 {
+		return strings.ReplaceAll(text, glue, " ")
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if visibleLen(line) <= width {
+			continue
+		}
+		var b strings.Builder
+		lineLen := 0
+		for _, word := range strings.Fields(line) {
+			wordLen := visibleLen(word)
+			if lineLen != 0 && lineLen+1+wordLen > width {
+				b.WriteString("\n")
+				lineLen = 0
+			}
+			if lineLen != 0 {
+				b.WriteString(" ")
+				lineLen++
+			}
+			b.WriteString(word)
+			lineLen += wordLen
+		}
+		lines[i] = b.String()
+	}
+	return strings.ReplaceAll(strings.Join(lines, "\n"), glue, " ")
+}
+
+// visibleLen returns the number of characters in the text, ignoring ANSI escape sequences.
+func visibleLen(s string) int {
+	n := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			inEscape = r != 'm'
+		case r == '\x1b':
+			inEscape = true
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,240 @@
+package cliff_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"text/template"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+	"github.com/spf13/pflag"
+)
+
+func TestMain(m *testing.M) {
+	// The help width depends on COLUMNS, unset it to keep the output stable.
+	os.Unsetenv("COLUMNS")
+	os.Exit(m.Run())
+}
+
+func TestHelp_Extras(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	spec := cliff.Spec{
+		Flags:       cliff.Flags{"port": cliff.F(&port, 'p', 8080, "port to listen to")},
+		Description: "Serve files from the current directory.",
+		Examples: []cliff.Example{
+			{Command: "example --port 80", Help: "serve on the default HTTP port"},
+			{Command: "example"},
+		},
+		Epilogue: "Report bugs at https://example.com/issues.",
+	}
+	var buf bytes.Buffer
+	err := spec.Parse(&buf, []string{"example", "--help"})
+	is.Equal(err, pflag.ErrHelp)
+	expected := `Usage: example [flags]
+
+Serve files from the current directory.
+
+Flags:
+  -p, --port int   port to listen to (default 8080)
+
+Examples:
+  # serve on the default HTTP port
+  example --port 80
+
+  example
+
+Report bugs at https://example.com/issues.
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestHelp_Usage(t *testing.T) {
+	is := is.New(t)
+
+	spec := cliff.Spec{Usage: "example [-p PORT]"}
+	var buf bytes.Buffer
+	err := spec.Parse(&buf, []string{"example", "-h"})
+	is.Equal(err, pflag.ErrHelp)
+	is.Equal(buf.String(), "Usage: example [-p PORT]\n")
+}
+
+func TestHelp_Template(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var name string
+	tmpl := template.Must(template.New("help").Parse(
+		`{{.Name}}: {{.Description}}
+{{range .Sections}}{{range .Flags}}--{{.Name}} ({{.Type}}, {{.Default}}, {{.Env}}): {{.Help}}
+{{end}}{{end}}`))
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"port": cliff.F(&port, 'p', 8080, "port to listen to").Env("PORT"),
+			"name": cliff.F(&name, 0, "", "user name"),
+		},
+		Description:  "serve files",
+		HelpRenderer: cliff.HelpTemplate(tmpl),
+	}
+	var buf bytes.Buffer
+	err := spec.Parse(&buf, []string{"example", "--help"})
+	is.Equal(err, pflag.ErrHelp)
+	expected := `example: serve files
+--name (string, , ): user name
+--port (int, 8080, PORT): port to listen to
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestHelp_RendererError(t *testing.T) {
+	is := is.New(t)
+
+	spec := cliff.Spec{
+		HelpRenderer: cliff.HelpFunc(func(w io.Writer, page cliff.HelpPage) error {
+			return errors.New("oh no")
+		}),
+	}
+	var buf bytes.Buffer
+	err := spec.Parse(&buf, []string{"example", "--help"})
+	is.Equal(err, pflag.ErrHelp)
+	is.Equal(buf.String(), "render help: oh no\n")
+}
+
+func TestHelp_PFlagSet(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	flags := cliff.Flags{"port": cliff.F(&port, 'p', 8080, "port to listen to").Env("PORT")}
+	var buf bytes.Buffer
+	pfs, err := flags.PFlagSet(&buf, "example")
+	is.NoErr(err)
+	err = pfs.Parse([]string{"--help"})
+	is.Equal(err, pflag.ErrHelp)
+	expected := `Usage: example [flags]
+
+Flags:
+  -p, --port int   port to listen to (default 8080) [$PORT]
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestHelp_Wrap(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var name string
+	env := map[string]string{"COLUMNS": "40"}
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"port": cliff.F(&port, 'p', 8080, "port to listen to, must be available and not blocked by firewall"),
+			"name": cliff.F(&name, 0, "", "a-very-long-word-that-cannot-be-split-anyhow"),
+		},
+		Description: "Serve files from the current directory over HTTP.",
+		LookupEnv: func(key string) (string, bool) {
+			val, found := env[key]
+			return val, found
+		},
+		Color: true,
+	}
+	var buf bytes.Buffer
+	err := spec.Parse(&buf, []string{"example", "--help"})
+	is.Equal(err, pflag.ErrHelp)
+	expected := `Usage: example [flags]
+
+Serve files from the current directory
+over HTTP.
+
+Flags:
+      --name string   a-very-long-word-that-cannot-be-split-anyhow
+  -p, --port int      port to listen to,
+                      must be available
+                      and not blocked by
+                      firewall
+                      (default 8080)
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestDefaultHelp_Color(t *testing.T) {
+	is := is.New(t)
+
+	page := cliff.HelpPage{
+		Usage: "example [flags]",
+		Sections: []cliff.HelpSection{{
+			Title: "Flags",
+			Flags: []cliff.HelpFlag{{
+				Name:      "name",
+				Shorthand: "n",
+				Help:      "user name",
+				Default:   "joe",
+				Names:     "-n, --name string",
+				Usage:     `user name (default "joe")`,
+			}},
+		}},
+		Width: 80,
+		Color: true,
+	}
+	var buf bytes.Buffer
+	err := cliff.DefaultHelp(&buf, page)
+	is.NoErr(err)
+	expected := "Usage: example [flags]\n\nFlags:\n" +
+		"  \x1b[1m-n, --name string\x1b[0m   user name (default \x1b[33m\"joe\"\x1b[0m)\n"
+	is.Equal(buf.String(), expected)
+}
+
+func TestDefaultHelp_WrapDefault(t *testing.T) {
+	is := is.New(t)
+
+	page := cliff.HelpPage{
+		Usage: "example [flags]",
+		Sections: []cliff.HelpSection{{
+			Title: "Flags",
+			Flags: []cliff.HelpFlag{{
+				Name:    "name",
+				Help:    "user name to greet",
+				Default: "joe doe",
+				Names:   "--name string",
+				Usage:   `user name to greet (default "joe doe")`,
+			}},
+		}},
+		Width: 40,
+		Color: true,
+	}
+	var buf bytes.Buffer
+	err := cliff.DefaultHelp(&buf, page)
+	is.NoErr(err)
+	expected := "Usage: example [flags]\n\nFlags:\n" +
+		"      \x1b[1m--name string\x1b[0m   user name to greet\n" +
+		"                      (default \x1b[33m\"joe doe\"\x1b[0m)\n"
+	is.Equal(buf.String(), expected)
+}
+
+func TestHelp_NoColor(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	spec := cliff.Spec{
+		Flags: cliff.Flags{"port": cliff.F(&port, 'p', 8080, "port")},
+		Color: true,
+		HelpRenderer: cliff.HelpFunc(func(w io.Writer, page cliff.HelpPage) error {
+			is.True(!page.Color)
+			is.Equal(page.Width, 80)
+			return nil
+		}),
+		LookupEnv: func(key string) (string, bool) {
+			if key == "NO_COLOR" {
+				return "1", true
+			}
+			return "", false
+		},
+	}
+	// Neither a terminal nor NO_COLOR allows colors.
+	err := spec.Parse(&bytes.Buffer{}, []string{"example", "--help"})
+	is.Equal(err, pflag.ErrHelp)
+	err = spec.Parse(os.Stderr, []string{"example", "--help"})
+	is.Equal(err, pflag.ErrHelp)
+}
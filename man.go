@@ -0,0 +1,185 @@
+package cliff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Man is the info about the program shown in a man page.
+type Man struct {
+	// Name is the program name.
+	Name string
+
+	// Section is the man page section. If zero, 1 (user commands) is used.
+	Section int
+
+	// Summary is a short one-line description shown next to the name.
+	Summary string
+
+	// Description is a longer description of the program.
+	//
+	// Empty lines separate paragraphs.
+	Description string
+
+	// Date is the date of the last change, shown in the page footer.
+	//
+	// It isn't generated automatically to keep the output reproducible.
+	Date string
+
+	// Source is the project name and version, like "example 1.2.0".
+	Source string
+
+	// Manual is the title of the manual, like "User Commands".
+	Manual string
+}
+
+// ManPage writes the man page in roff format.
+//
+// Hidden flags are not included. Deprecated flags are included
+// together with the deprecation message.
+//
+// Typical usage:
+//
+//	flags.ManPage(os.Stdout, cliff.Man{Name: "example", Summary: "do something"})
+func (fs Flags) ManPage(w io.Writer, man Man) error {
+	pfs, err := fs.PFlagSet(io.Discard, man.Name)
+	if err != nil {
+		return err
+	}
+	writeMan(w, man, fs, pfs)
+	return nil
+}
+
+func writeMan(w io.Writer, man Man, fs Flags, pfs *pflag.FlagSet) {
+	section := man.Section
+	if section == 0 {
+		section = 1
+	}
+	fmt.Fprintf(w, ".TH %s %d %s %s %s\n",
+		roffQuote(strings.ToUpper(man.Name)), section,
+		roffQuote(man.Date), roffQuote(man.Source), roffQuote(man.Manual),
+	)
+
+	fmt.Fprintln(w, ".SH NAME")
+	if man.Summary != "" {
+		fmt.Fprintf(w, "%s \\- %s\n", roffEscape(man.Name), roffEscape(man.Summary))
+	} else {
+		fmt.Fprintln(w, roffEscape(man.Name))
+	}
+
+	flags := make([]*pflag.Flag, 0)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		// Negations and aliases are shown together with the flag they belong to.
+		if pf.Annotations[annotationNegates] != nil || pf.Annotations[annotationAliasOf] != nil {
+			return
+		}
+		// pflag marks deprecated flags as hidden,
+		// so the hidden bit is taken from the flag definition.
+		if fs[pf.Name].hidden {
+			return
+		}
+		flags = append(flags, pf)
+	})
+
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintf(w, ".B %s\n", roffEscape(man.Name))
+	if len(flags) != 0 {
+		fmt.Fprintln(w, "[\\fIflags\\fR]")
+	}
+
+	if man.Description != "" {
+		fmt.Fprintln(w, ".SH DESCRIPTION")
+		for i, par := range strings.Split(strings.TrimSpace(man.Description), "\n\n") {
+			if i != 0 {
+				fmt.Fprintln(w, ".PP")
+			}
+			fmt.Fprintln(w, roffEscape(par))
+		}
+	}
+
+	envs := make([]*pflag.Flag, 0)
+	if len(flags) != 0 {
+		fmt.Fprintln(w, ".SH OPTIONS")
+	}
+	for _, pf := range flags {
+		writeManFlag(w, pf)
+		if getEnv(pf) != "" {
+			envs = append(envs, pf)
+		}
+	}
+
+	if len(envs) != 0 {
+		fmt.Fprintln(w, ".SH ENVIRONMENT")
+		for _, pf := range envs {
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintf(w, ".B %s\n", roffEscape(getEnv(pf)))
+			fmt.Fprintf(w, "Same as \\fB\\-\\-%s\\fR.\n", roffEscape(pf.Name))
+		}
+	}
+}
+
+// writeManFlag writes the description of a single flag for the OPTIONS section.
+func writeManFlag(w io.Writer, pf *pflag.Flag) {
+	fmt.Fprintln(w, ".TP")
+	line := fmt.Sprintf("\\fB%s\\fR", roffEscape(longName(pf)))
+	if pf.Shorthand != "" && pf.ShorthandDeprecated == "" {
+		line = fmt.Sprintf("\\fB\\-%s\\fR, %s", roffEscape(pf.Shorthand), line)
+	}
+	varname, usage := pflag.UnquoteUsage(pf)
+	if varname != "" {
+		line += fmt.Sprintf(" \\fI%s\\fR", roffEscape(varname))
+	}
+	fmt.Fprintln(w, line)
+
+	notes := make([]string, 0)
+	if usage != "" {
+		notes = append(notes, usage)
+	}
+	choices := pf.Annotations[annotationChoices]
+	if len(choices) != 0 {
+		notes = append(notes, fmt.Sprintf("One of: %s.", strings.Join(choices, ", ")))
+	}
+	if !isZeroFlagDefault(pf) {
+		notes = append(notes, fmt.Sprintf("Default: %s.", pf.DefValue))
+	}
+	env := getEnv(pf)
+	if env != "" {
+		notes = append(notes, fmt.Sprintf("Environment variable: %s.", env))
+	}
+	if isRequired(pf) {
+		notes = append(notes, "Required.")
+	}
+	if pf.Deprecated != "" {
+		notes = append(notes, fmt.Sprintf("Deprecated: %s.", pf.Deprecated))
+	}
+	if pf.ShorthandDeprecated != "" {
+		notes = append(notes, fmt.Sprintf("Short alias -%s is deprecated: %s.", pf.Shorthand, pf.ShorthandDeprecated))
+	}
+	for i, note := range notes {
+		if i != 0 {
+			fmt.Fprintln(w, ".br")
+		}
+		fmt.Fprintln(w, roffEscape(note))
+	}
+}
+
+// roffEscape escapes the text so that roff shows it as is.
+func roffEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\e`, "-", `\-`)
+	lines := strings.Split(r.Replace(s), "\n")
+	for i, line := range lines {
+		// Lines starting with a dot or an apostrophe are roff requests.
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roffQuote escapes and quotes an argument of a roff request.
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `""`) + `"`
+}
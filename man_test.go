@@ -0,0 +1,129 @@
+package cliff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestManPage(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var host, format, old, secret string
+	formats := map[string]string{"json": "json", "text": "text"}
+	flags := cliff.Flags{
+		"port":   cliff.F(&port, 'p', 8080, "port to listen to").Env("PORT"),
+		"host":   cliff.F(&host, 'h', "", "host to serve on").ShortDeprecated("use --host"),
+		"format": cliff.Enum(&format, 0, "text", formats, "output format"),
+		"old":    cliff.F(&old, 0, "", `.don't use \ it`).Deprecated("use --host"),
+		"secret": cliff.F(&secret, 0, "", "").Hidden(),
+		"sec":    cliff.F(&secret, 0, "", "").Hidden().Deprecated("gone"),
+		"name":   cliff.R(&secret, 'n', "user name"),
+	}
+	var buf bytes.Buffer
+	err := flags.ManPage(&buf, cliff.Man{
+		Name:        "my-app",
+		Summary:     "serve things",
+		Description: "First paragraph.\n\n.Second paragraph.",
+		Date:        "2024-01-01",
+		Source:      "my-app 1.0",
+		Manual:      "User Commands",
+	})
+	is.NoErr(err)
+	expected := `.TH "MY\-APP" 1 "2024\-01\-01" "my\-app 1.0" "User Commands"
+.SH NAME
+my\-app \- serve things
+.SH SYNOPSIS
+.B my\-app
+[\fIflags\fR]
+.SH DESCRIPTION
+First paragraph.
+.PP
+\&.Second paragraph.
+.SH OPTIONS
+.TP
+\fB\-\-format\fR \fIstring\fR
+output format
+.br
+One of: json, text.
+.br
+Default: text.
+.TP
+\fB\-\-host\fR \fIstring\fR
+host to serve on
+.br
+Short alias \-h is deprecated: use \-\-host.
+.TP
+\fB\-n\fR, \fB\-\-name\fR \fIstring\fR
+user name
+.br
+Required.
+.TP
+\fB\-\-old\fR \fIstring\fR
+\&.don't use \e it
+.br
+Deprecated: use \-\-host.
+.TP
+\fB\-p\fR, \fB\-\-port\fR \fIint\fR
+port to listen to
+.br
+Default: 8080.
+.br
+Environment variable: PORT.
+.SH ENVIRONMENT
+.TP
+.B PORT
+Same as \fB\-\-port\fR.
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestManPage_NoFlags(t *testing.T) {
+	is := is.New(t)
+
+	var buf bytes.Buffer
+	err := cliff.Flags{}.ManPage(&buf, cliff.Man{Name: "app", Section: 8})
+	is.NoErr(err)
+	expected := `.TH "APP" 8 "" "" ""
+.SH NAME
+app
+.SH SYNOPSIS
+.B app
+`
+	is.Equal(buf.String(), expected)
+}
+
+func TestManPage_Companions(t *testing.T) {
+	is := is.New(t)
+
+	var old, https bool
+	flags := cliff.Flags{
+		"old":   cliff.F(&old, 0, true, "").Negatable().Alias("legacy").Deprecated("use --new"),
+		"https": cliff.F(&https, 0, true, "use HTTPS").Negatable(),
+	}
+	var buf bytes.Buffer
+	err := flags.ManPage(&buf, cliff.Man{Name: "app"})
+	is.NoErr(err)
+	expected := `.TH "APP" 1 "" "" ""
+.SH NAME
+app
+.SH SYNOPSIS
+.B app
+[\fIflags\fR]
+.SH OPTIONS
+.TP
+\fB\-\-[no\-]https\fR
+use HTTPS
+.br
+Default: true.
+.TP
+\fB\-\-[no\-]old, \-\-legacy\fR
+Default: true.
+.br
+Deprecated: use \-\-new.
+`
+	is.Equal(buf.String(), expected)
+}
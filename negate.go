@@ -0,0 +1,93 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// annotationNegatable is the [pflag.Flag] annotation with the name of the negation flag.
+const annotationNegatable = "cliff-negatable"
+
+// annotationNegates is the [pflag.Flag] annotation of the negation flag
+// with the name of the negated flag.
+const annotationNegates = "cliff-negates"
+
+// negValue is the value of a "--no-<name>" flag that sets the negated flag to the opposite.
+type negValue struct {
+	target pflag.Value
+}
+
+func (v negValue) String() string {
+	b, _ := strconv.ParseBool(v.target.String())
+	return strconv.FormatBool(!b)
+}
+
+func (v negValue) Set(raw string) error {
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return err
+	}
+	return v.target.Set(strconv.FormatBool(!b))
+}
+
+func (v negValue) Type() string {
+	return "bool"
+}
+
+// IsBoolFlag allows passing the flag without a value in [flag.FlagSet].
+func (v negValue) IsBoolFlag() bool {
+	return true
+}
+
+// addNegation adds the hidden "--no-<name>" flag for the boolean flag with the given name.
+func addNegation(fs *pflag.FlagSet, name string) error {
+	pf := fs.Lookup(name)
+	if pf.Value.Type() != "bool" {
+		return errors.New("only boolean flags can be negatable")
+	}
+	negName := "no-" + name
+	if fs.Lookup(negName) != nil {
+		return fmt.Errorf("flag %s is already defined", negName)
+	}
+	val := negValue{target: pf.Value}
+	fs.VarPF(val, negName, "", "").NoOptDefVal = "true"
+	neg := fs.Lookup(negName)
+	neg.Hidden = true
+	neg.Deprecated = pf.Deprecated
+	neg.Annotations = map[string][]string{annotationNegates: {name}}
+	return fs.SetAnnotation(name, annotationNegatable, []string{negName})
+}
+
+// resolveNegations checks that a flag and its negation are not passed together
+// and marks negated flags as passed.
+func resolveNegations(pfs *pflag.FlagSet) error {
+	var err error
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		negName := pf.Annotations[annotationNegatable]
+		if err != nil || negName == nil {
+			return
+		}
+		neg := pfs.Lookup(negName[0])
+		if !neg.Changed {
+			return
+		}
+		if pf.Changed {
+			err = fmt.Errorf("flags --%s and --%s cannot be used together", pf.Name, neg.Name)
+			return
+		}
+		pf.Changed = true
+	})
+	return err
+}
+
+// longName returns the long names of the flag with dashes,
+// like "--[no-]https" for negatable flags or "--listen, --addr" for flags with aliases.
+func longName(pf *pflag.Flag) string {
+	if pf.Annotations[annotationNegatable] != nil {
+		return "--[no-]" + pf.Name + aliasNames(pf)
+	}
+	return "--" + pf.Name + aliasNames(pf)
+}
@@ -0,0 +1,149 @@
+package cliff_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestNegatable(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		https  bool
+		result cliff.Result
+	}
+	init := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"https": cliff.F(&c.https, 's', true, "use HTTPS").Negatable().Env("HTTPS"),
+			},
+			LookupEnv: func(key string) (string, bool) {
+				return "true", key == "HTTPS"
+			},
+			Result: &c.result,
+		}
+	}
+	parse := func(args ...string) (Config, error) {
+		return cliff.Parse(io.Discard, append([]string{"example"}, args...), init)
+	}
+
+	c, err := parse()
+	is.NoErr(err)
+	is.Equal(c.https, true)
+	is.Equal(c.result.Source("https"), cliff.SourceEnv)
+
+	c, err = parse("--no-https")
+	is.NoErr(err)
+	is.Equal(c.https, false)
+	is.Equal(c.result.Source("https"), cliff.SourceCLI)
+
+	c, err = parse("--no-https=false")
+	is.NoErr(err)
+	is.Equal(c.https, true)
+
+	c, err = parse("-s=false")
+	is.NoErr(err)
+	is.Equal(c.https, false)
+
+	_, err = parse("--https", "--no-https")
+	is.Equal(err.Error(), "flags --https and --no-https cannot be used together")
+
+	var help bytes.Buffer
+	_, _ = cliff.Parse(&help, []string{"example", "--help"}, init)
+	is.Equal(help.String(), "Usage: example [flags]\n\nFlags:\n  -s, --[no-]https   use HTTPS (default true) [$HTTPS]\n")
+}
+
+func TestNegatable_Required(t *testing.T) {
+	is := is.New(t)
+
+	var debug bool
+	flags := cliff.Flags{"debug": cliff.F(&debug, 0, true, "").Negatable().Required()}
+	err := flags.Parse(io.Discard, []string{"example", "--no-debug"})
+	is.NoErr(err)
+	is.Equal(debug, false)
+}
+
+func TestNegatable_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var https, noHTTPS bool
+	flags := cliff.Flags{"port": cliff.F(&port, 0, 0, "").Negatable()}
+	_, err := flags.PFlagSet(io.Discard, "example")
+	is.Equal(err.Error(), "add flag port: only boolean flags can be negatable")
+
+	flags = cliff.Flags{
+		"https":    cliff.F(&https, 0, true, "").Negatable(),
+		"no-https": cliff.F(&noHTTPS, 0, false, ""),
+	}
+	_, err = flags.PFlagSet(io.Discard, "example")
+	is.Equal(err.Error(), "conflicting flags: --no-https is defined by negation of --https and flag --no-https")
+}
+
+func TestNegatable_FlagSet(t *testing.T) {
+	is := is.New(t)
+
+	var https bool
+	flags := cliff.Flags{"https": cliff.F(&https, 0, true, "").Negatable()}
+	gfs, err := flags.FlagSet(io.Discard, "example")
+	is.NoErr(err)
+	err = gfs.Parse([]string{"--no-https"})
+	is.NoErr(err)
+	is.Equal(https, false)
+}
+
+func TestNegatable_Completion(t *testing.T) {
+	is := is.New(t)
+
+	var https, secret bool
+	flags := cliff.Flags{
+		"https":  cliff.F(&https, 0, true, "").Negatable(),
+		"secret": cliff.F(&secret, 0, true, "").Negatable().Hidden(),
+	}
+	var buf bytes.Buffer
+	err := flags.Completion(&buf, cliff.Fish, "example")
+	is.NoErr(err)
+	is.True(strings.Contains(buf.String(), "-l no-https"))
+	is.True(!strings.Contains(buf.String(), "secret"))
+}
+
+func TestNegatable_Config(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		config string
+		https  bool
+		result cliff.Result
+	}
+	init := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"config": cliff.F(&c.config, 'c', "", ""),
+				"https":  cliff.F(&c.https, 0, true, "").Negatable(),
+			},
+			ConfigFlag: "config",
+			ReadFile: func(path string) ([]byte, error) {
+				return []byte(`{"no-https": true}`), nil
+			},
+			Result: &c.result,
+		}
+	}
+	parse := func(args ...string) (Config, error) {
+		return cliff.Parse(io.Discard, append([]string{"example", "-c", "app.json"}, args...), init)
+	}
+
+	c, err := parse()
+	is.NoErr(err)
+	is.Equal(c.https, false)
+	is.Equal(c.result.Source("https"), cliff.SourceConfig)
+
+	c, err = parse("--https")
+	is.NoErr(err)
+	is.Equal(c.https, true)
+	is.Equal(c.result.Source("https"), cliff.SourceCLI)
+}
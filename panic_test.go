@@ -0,0 +1,207 @@
+package cliff_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/netip"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+// flagsFromBytes builds arbitrary, possibly invalid, flag definitions from the given bytes.
+func flagsFromBytes(data []byte) cliff.Flags {
+	names := []string{"a", "b", "p", "port", "no-a", "no-port", "Port", "-a", "a=b", "", "a--b", "é"}
+	shorts := []cliff.Short{0, 'a', 'b', 'p', 'h', '-', 'é', ' '}
+	next := func() int {
+		if len(data) == 0 {
+			return 0
+		}
+		b := data[0]
+		data = data[1:]
+		return int(b)
+	}
+	flags := cliff.Flags{}
+	for len(data) != 0 {
+		name := names[next()%len(names)]
+		short := shorts[next()%len(shorts)]
+		var f cliff.Flag
+		switch next() % 11 {
+		case 0:
+			f = cliff.F(new(bool), short, false, "")
+		case 1:
+			f = cliff.F(new(int), short, 0, "")
+		case 2:
+			f = cliff.F((*string)(nil), short, "", "")
+		case 3:
+			f = cliff.F(new(cliff.Count), short, 0, "")
+		case 4:
+			f = cliff.Enum(new(int), short, 1, map[string]int{"one": 1}, "")
+		case 5:
+			f = cliff.Enum((*int)(nil), short, 1, map[string]int{}, "")
+		case 6:
+			f = cliff.FuncFlag(new(int), short, 0, func(string) (int, error) { return 0, nil }, "")
+		case 7:
+			f = cliff.FuncFlag[int](nil, short, 0, nil, "")
+		case 8:
+			gfs := flag.NewFlagSet("", flag.ContinueOnError)
+			gfs.Bool("x", false, "")
+			f = cliff.GoFlag(short, gfs.Lookup("x"))
+		case 9:
+			f = cliff.GoFlag(short, nil)
+		case 10:
+			f = cliff.TextFlag(new(netip.Addr), short, netip.Addr{}, "")
+		}
+		mods := next()
+		if mods&1 != 0 {
+			f = f.Negatable()
+		}
+		if mods&2 != 0 {
+			f = f.Alias(names[next()%len(names)])
+		}
+		if mods&4 != 0 {
+			f = f.Hidden()
+		}
+		if mods&8 != 0 {
+			f = f.Deprecated("deprecated")
+		}
+		if mods&16 != 0 {
+			f = f.ShortDeprecated("deprecated")
+		}
+		if mods&32 != 0 {
+			f = f.Env("A")
+		}
+		if mods&64 != 0 {
+			f = f.Required()
+		}
+		if mods&128 != 0 {
+			f = cliff.Flag{}
+		}
+		flags[name] = f
+	}
+	return flags
+}
+
+// checkNoPanic registers the flags in all supported ways and fails if anything panics.
+func checkNoPanic(t *testing.T, data []byte) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if r != nil {
+			t.Fatalf("panic for %v: %v", data, r)
+		}
+	}()
+	flags := flagsFromBytes(data)
+	_, _ = flags.PFlagSet(io.Discard, "example")
+	_, _ = flags.FlagSet(io.Discard, "example")
+	_ = flags.CheckConflicts(nil)
+	_ = flags.Parse(io.Discard, []string{"example", "--help"})
+	for name := range flags {
+		pfs, err := flags.PFlagSet(io.Discard, "example")
+		if err == nil {
+			_ = pfs.Parse([]string{"--" + name + "=one"})
+			_ = flags.CheckPFlagSet(pfs)
+		}
+		gfs, err := flags.FlagSet(io.Discard, "example")
+		if err == nil {
+			_ = gfs.Parse([]string{"-" + name + "=one"})
+			_ = flags.CheckFlagSet(gfs)
+		}
+	}
+}
+
+func FuzzFlags(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{2, 2, 0, 0})
+	f.Add([]byte{0, 3, 0, 0, 3, 3, 1, 0})
+	f.Add([]byte{2, 1, 0, 1, 4, 1, 1, 2, 3})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkNoPanic(t, data)
+	})
+}
+
+func TestFlags_NeverPanic(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		data := make([]byte, rnd.Intn(40))
+		rnd.Read(data)
+		checkNoPanic(t, data)
+	}
+}
+
+func TestFlags_RegistrationErrors(t *testing.T) {
+	is := is.New(t)
+
+	var a, b int
+	cases := []struct {
+		flags cliff.Flags
+		err   string
+	}{
+		{
+			cliff.Flags{"a": cliff.GoFlag(0, nil)},
+			"add flag a: go flag must not be nil",
+		},
+		{
+			cliff.Flags{"a": cliff.GoFlag(0, flag.Lookup("not-defined"))},
+			"add flag a: go flag must not be nil",
+		},
+		{
+			cliff.Flags{"a": cliff.F((*int)(nil), 0, 0, "")},
+			"add flag a: target must not be nil",
+		},
+		{
+			cliff.Flags{"a": cliff.FuncFlag[int](nil, 0, 0, nil, "")},
+			"add flag a: target must not be nil",
+		},
+		{
+			cliff.Flags{"a": cliff.FuncFlag(&a, 0, 0, nil, "")},
+			"add flag a: parser must not be nil",
+		},
+		{
+			cliff.Flags{"a": cliff.Flag{}},
+			"add flag a: flag must be created using a constructor, like F",
+		},
+		{
+			cliff.Flags{"a": cliff.F(&a, 'b', 0, ""), "b": cliff.F(&b, 0, 0, "")},
+			"",
+		},
+	}
+	for _, c := range cases {
+		_, err := c.flags.PFlagSet(io.Discard, "example")
+		if c.err == "" {
+			is.NoErr(err)
+			continue
+		}
+		is.True(err != nil)
+		is.Equal(err.Error(), c.err)
+	}
+}
+
+func TestFlags_FlagSet_Conflicts(t *testing.T) {
+	is := is.New(t)
+
+	var a, b, port int
+	flags := cliff.Flags{
+		"a":    cliff.F(&a, 'p', 0, ""),
+		"p":    cliff.F(&b, 'a', 0, ""),
+		"port": cliff.F(&port, 0, 0, ""),
+	}
+	_, err := flags.FlagSet(io.Discard, "example")
+	is.Equal(err.Error(), "conflicting flags: "+
+		"-p is used by shorthand of --a and flag --p; "+
+		"-a is used by flag --a and shorthand of --p")
+	_, err = flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+}
+
+func ExampleGoFlag_nil() {
+	flags := cliff.Flags{
+		"verbose": cliff.GoFlag('v', flag.Lookup("not-defined")),
+	}
+	_, err := flags.PFlagSet(io.Discard, "example")
+	fmt.Println(err)
+	// Output: add flag verbose: go flag must not be nil
+}
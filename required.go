@@ -0,0 +1,149 @@
+package cliff
+
+import (
+	"flag"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// annotationRequired is the [pflag.Flag] annotation marking the flag as required.
+//
+// It's the same annotation as used by cobra, so cobra also checks required flags.
+const annotationRequired = "cobra_annotation_bash_completion_one_required_flag"
+
+// MissingFlagsError is returned when some of the required flags are not set.
+type MissingFlagsError struct {
+	Names []string // sorted names of all missing flags
+}
+
+func (e MissingFlagsError) Error() string {
+	names := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		names[i] = "--" + name
+	}
+	return "missing required flags: " + strings.Join(names, ", ")
+}
+
+// CheckPFlagSet checks that all required flags are set in the parsed [pflag.FlagSet]
+// and values of all passed flags pass validation.
+//
+// Flags passed using an alias or a negation are considered set.
+//
+// Use it after parsing the flag set returned by [Flags.PFlagSet].
+func (fs Flags) CheckPFlagSet(pfs *pflag.FlagSet) error {
+	resolveAliases(pfs)
+	err := resolveNegations(pfs)
+	if err != nil {
+		return err
+	}
+	return fs.checkRequired(func(name string) (string, bool) {
+		pf := pfs.Lookup(name)
+		if pf == nil {
+			return "", false
+		}
+		return pf.Value.String(), pf.Changed
+	})
+}
+
+// CheckFlagSet checks that all required flags are set in the parsed [flag.FlagSet]
+// and values of all passed flags pass validation.
+//
+// Use it after parsing the flag set returned by [Flags.FlagSet].
+func (fs Flags) CheckFlagSet(gfs *flag.FlagSet) error {
+	owners := fs.owners()
+	set := make(map[string]bool)
+	gfs.Visit(func(f *flag.Flag) {
+		set[owners[f.Name]] = true
+	})
+	return fs.checkRequired(func(name string) (string, bool) {
+		f := gfs.Lookup(name)
+		if f == nil {
+			return "", false
+		}
+		return f.Value.String(), set[name]
+	})
+}
+
+// owners maps all names of the flags, including shorthands, aliases, and negations,
+// to the name of the flag they belong to.
+func (fs Flags) owners() map[string]string {
+	owners := make(map[string]string)
+	for _, name := range fs.names() {
+		f := fs[name]
+		owners[name] = name
+		for _, alias := range f.aliases {
+			owners[alias] = name
+		}
+		if f.negatable {
+			owners["no-"+name] = name
+		}
+		if f.setter != nil && f.setter.shorthand() != "" {
+			owners[f.setter.shorthand()] = name
+		}
+	}
+	return owners
+}
+
+// checkRequired checks required flags and validators
+// using the lookup function returning the flag value and if it's set.
+func (fs Flags) checkRequired(lookup func(name string) (string, bool)) error {
+	missing := make([]string, 0)
+	for name, f := range fs {
+		_, set := lookup(name)
+		if f.required && !set {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return MissingFlagsError{Names: missing}
+	}
+
+	for _, name := range fs.names() {
+		check := fs[name].check
+		val, set := lookup(name)
+		if check == nil || !set {
+			continue
+		}
+		err := check()
+		if err != nil {
+			return invalidValue(name, val, err)
+		}
+	}
+	return nil
+}
+
+// checkRequired checks that all required flags in the parsed flag set are set.
+//
+// The set contains flags set from env vars or config files.
+// The flags from skip list are not checked.
+func checkRequired(pfs *pflag.FlagSet, set map[*pflag.Flag]Source, skip []*pflag.Flag) error {
+	skipped := toSet(skip)
+	missing := make([]string, 0)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		if !isRequired(pf) || flagSource(pf, set) != SourceDefault || skipped[pf] {
+			return
+		}
+		missing = append(missing, pf.Name)
+	})
+	if len(missing) == 0 {
+		return nil
+	}
+	return MissingFlagsError{Names: missing}
+}
+
+// isRequired checks if the flag is marked as required.
+func isRequired(pf *pflag.Flag) bool {
+	req := pf.Annotations[annotationRequired]
+	return len(req) != 0 && req[0] == "true"
+}
+
+func toSet(pfs []*pflag.Flag) map[*pflag.Flag]bool {
+	set := make(map[*pflag.Flag]bool, len(pfs))
+	for _, pf := range pfs {
+		set[pf] = true
+	}
+	return set
+}
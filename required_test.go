@@ -0,0 +1,136 @@
+package cliff_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestRequired(t *testing.T) {
+	is := is.New(t)
+
+	var host string
+	var port int
+	var debug bool
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"host":  cliff.R(&host, 0, ""),
+			"port":  cliff.F(&port, 'p', 8080, "").Required().Env("PORT"),
+			"debug": cliff.F(&debug, 'd', false, ""),
+		},
+		LookupEnv: func(key string) (string, bool) { return "", false },
+	}
+	err := spec.Parse(io.Discard, []string{"example", "-d"})
+	var missing cliff.MissingFlagsError
+	is.True(errors.As(err, &missing))
+	is.Equal(missing.Names, []string{"host", "port"})
+	is.Equal(err.Error(), "missing required flags: --host, --port")
+
+	spec.LookupEnv = func(key string) (string, bool) { return "80", true }
+	err = spec.Parse(io.Discard, []string{"example", "--host", "localhost"})
+	is.NoErr(err)
+	is.Equal(host, "localhost")
+	is.Equal(port, 80)
+}
+
+func TestRequired_Persistent(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct{ token string }
+	noop := func(Config) error { return nil }
+	root := cliff.Cmd(func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"token": cliff.R(&c.token, 't', "").Persistent(),
+			},
+			Commands: cliff.Commands{
+				"sub": cliff.Cmd(func(*Config) cliff.Flags { return nil }, noop, ""),
+			},
+		}
+	}, noop, "")
+	run := func(args ...string) error {
+		return cliff.Run(io.Discard, append([]string{"example"}, args...), root)
+	}
+	is.NoErr(run("sub", "-t", "secret"))
+	is.NoErr(run("-t", "secret", "sub"))
+	is.NoErr(run("-t", "secret"))
+	is.Equal(run("sub").Error(), "missing required flags: --token")
+	is.Equal(run().Error(), "missing required flags: --token")
+}
+
+func TestFlags_CheckPFlagSet(t *testing.T) {
+	is := is.New(t)
+
+	var host string
+	flags := cliff.Flags{"host": cliff.R(&host, 'h', "")}
+	pfs, err := flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(pfs.Parse(nil))
+	is.Equal(flags.CheckPFlagSet(pfs).Error(), "missing required flags: --host")
+
+	pfs, err = flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(pfs.Parse([]string{"-h", "localhost"}))
+	is.NoErr(flags.CheckPFlagSet(pfs))
+
+	var https bool
+	flags = cliff.Flags{
+		"host":  cliff.R(&host, 'h', "").Alias("addr"),
+		"https": cliff.F(&https, 0, true, "").Negatable().Required(),
+	}
+	pfs, err = flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(pfs.Parse([]string{"--addr", "localhost", "--no-https"}))
+	is.NoErr(flags.CheckPFlagSet(pfs))
+	is.Equal(host, "localhost")
+	is.Equal(https, false)
+
+	pfs, err = flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(pfs.Parse([]string{"--addr", "localhost", "--https", "--no-https"}))
+	is.Equal(flags.CheckPFlagSet(pfs).Error(), "flags --https and --no-https cannot be used together")
+}
+
+func TestFlags_CheckFlagSet(t *testing.T) {
+	is := is.New(t)
+
+	var host string
+	flags := cliff.Flags{"host": cliff.R(&host, 'h', "")}
+	gfs, err := flags.FlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(gfs.Parse(nil))
+	is.Equal(flags.CheckFlagSet(gfs).Error(), "missing required flags: --host")
+
+	gfs, err = flags.FlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(gfs.Parse([]string{"-h", "localhost"}))
+	is.NoErr(flags.CheckFlagSet(gfs))
+
+	var https bool
+	flags = cliff.Flags{
+		"host":  cliff.R(&host, 'h', "").Alias("addr"),
+		"https": cliff.F(&https, 0, true, "").Negatable().Required(),
+	}
+	gfs, err = flags.FlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(gfs.Parse([]string{"-addr", "localhost", "-no-https"}))
+	is.NoErr(flags.CheckFlagSet(gfs))
+	is.Equal(host, "localhost")
+	is.Equal(https, false)
+
+	var format int
+	var size int
+	flags = cliff.Flags{
+		"format": cliff.Enum(&format, 'f', 0, map[string]int{"one": 1}, "").Required(),
+		"size":   cliff.FuncFlag(&size, 0, 0, func(string) (int, error) { return 2, nil }, "").Required(),
+	}
+	gfs, err = flags.FlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(gfs.Parse([]string{"-f", "one", "-size", "x"}))
+	is.NoErr(flags.CheckFlagSet(gfs))
+	is.Equal(format, 1)
+	is.Equal(size, 2)
+}
@@ -0,0 +1,70 @@
+package cliff
+
+import "github.com/spf13/pflag"
+
+// Source is where the flag value came from.
+type Source int
+
+const (
+	// SourceDefault means the flag is not set and has the default value.
+	SourceDefault Source = iota
+	// SourceCLI means the flag is passed explicitly in CLI arguments.
+	SourceCLI
+	// SourceEnv means the flag value is read from an env var.
+	SourceEnv
+	// SourceConfig means the flag value is read from a config file.
+	SourceConfig
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceCLI:
+		return "cli"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	}
+	return "unknown"
+}
+
+// Result is the info about the parsed flags.
+//
+// Set [Spec.Result] to get it.
+type Result struct {
+	sources map[string]Source
+}
+
+// Source returns where the value of the flag with the given name came from.
+//
+// For unknown flags, [SourceDefault] is returned.
+func (r Result) Source(name string) Source {
+	return r.sources[name]
+}
+
+// IsSet checks if the flag with the given name is set from any source
+// and doesn't have the default value.
+func (r Result) IsSet(name string) bool {
+	return r.Source(name) != SourceDefault
+}
+
+// newResult collects sources of all flags in the parsed flag set.
+//
+// The set contains flags set from env vars or config files.
+func newResult(pfs *pflag.FlagSet, set map[*pflag.Flag]Source) Result {
+	sources := make(map[string]Source)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		sources[pf.Name] = flagSource(pf, set)
+	})
+	return Result{sources: sources}
+}
+
+// flagSource returns where the value of the parsed flag came from.
+func flagSource(pf *pflag.Flag, set map[*pflag.Flag]Source) Source {
+	if pf.Changed {
+		return SourceCLI
+	}
+	return set[pf]
+}
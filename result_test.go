@@ -0,0 +1,88 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestResult(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		port, workers, timeout, retries int
+		config                          string
+		result                          cliff.Result
+	}
+	init := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"port":    cliff.F(&c.port, 'p', 8080, ""),
+				"workers": cliff.F(&c.workers, 0, 1, "").Env("WORKERS"),
+				"timeout": cliff.F(&c.timeout, 0, 10, ""),
+				"retries": cliff.F(&c.retries, 0, 3, ""),
+				"config":  cliff.F(&c.config, 0, "", ""),
+			},
+			ConfigFlag: "config",
+			LookupEnv: func(key string) (string, bool) {
+				return "4", key == "WORKERS"
+			},
+			ReadFile: func(path string) ([]byte, error) {
+				return []byte(`{"timeout": 30, "port": 9000}`), nil
+			},
+			Result: &c.result,
+		}
+	}
+	args := []string{"example", "-p", "80", "--config", "c.json"}
+	c, err := cliff.Parse(io.Discard, args, init)
+	is.NoErr(err)
+	is.Equal(c.port, 80)
+	is.Equal(c.timeout, 30)
+
+	r := c.result
+	is.Equal(r.Source("port"), cliff.SourceCLI)
+	is.Equal(r.Source("workers"), cliff.SourceEnv)
+	is.Equal(r.Source("timeout"), cliff.SourceConfig)
+	is.Equal(r.Source("retries"), cliff.SourceDefault)
+	is.Equal(r.Source("config"), cliff.SourceCLI)
+	is.Equal(r.Source("unknown"), cliff.SourceDefault)
+	is.True(r.IsSet("timeout"))
+	is.True(!r.IsSet("retries"))
+
+	is.Equal(cliff.SourceConfig.String(), "config")
+	is.Equal(cliff.Source(42).String(), "unknown")
+}
+
+func TestResult_Subcommand(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct {
+		debug  bool
+		port   int
+		result cliff.Result
+	}
+	var sub Config
+	serve := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags:  cliff.Flags{"port": cliff.F(&c.port, 0, 80, "")},
+			Result: &c.result,
+		}
+	}
+	init := func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{"debug": cliff.F(&c.debug, 'd', false, "").Persistent()},
+			Commands: cliff.Commands{
+				"serve": cliff.Cmd(serve, func(c Config) error {
+					sub = c
+					return nil
+				}, ""),
+			},
+		}
+	}
+	_, err := cliff.Parse(io.Discard, []string{"example", "serve", "-d"}, init)
+	is.NoErr(err)
+	is.Equal(sub.result.Source("debug"), cliff.SourceCLI)
+	is.Equal(sub.result.Source("port"), cliff.SourceDefault)
+}
@@ -0,0 +1,109 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/pflag"
+)
+
+// Section is a titled list of flags shown together in help.
+type Section struct {
+	Title string
+	Flags []string // names of the flags in the order they are shown
+}
+
+// validateSections checks that sections refer only to known flags and don't overlap.
+func validateSections(pfs *pflag.FlagSet, sections []Section) error {
+	seen := make(map[string]string)
+	for _, sec := range sections {
+		if sec.Title == "" {
+			return errors.New("section title must not be empty")
+		}
+		for _, name := range sec.Flags {
+			pf := pfs.Lookup(name)
+			if pf == nil {
+				return fmt.Errorf("section %s refers to unknown flag: %s", sec.Title, name)
+			}
+			// Aliases and negations are hidden, they are shown together with their flag.
+			if owner := ownerOf(pfs, pf); owner != pf {
+				return fmt.Errorf("section %s refers to %s, which is %s", sec.Title, name, flagOwner(pf))
+			}
+			prev, found := seen[name]
+			if found {
+				return fmt.Errorf("flag %s is in both sections %s and %s", name, prev, sec.Title)
+			}
+			seen[name] = sec.Title
+		}
+	}
+	return nil
+}
+
+// flagSection is a titled list of flags to be shown in help.
+type flagSection struct {
+	title string
+	flags []*pflag.Flag
+}
+
+// flagSections splits all visible flags into sections to be shown in help.
+//
+// Flags not in any of the sections are in sorted order in a section titled "Flags",
+// or "Other flags" if there are other sections. The inherited flags are in "Global flags".
+// Empty sections are omitted.
+func flagSections(pfs *pflag.FlagSet, sections []Section, inherited []*pflag.Flag) []flagSection {
+	all := make([]flagSection, 0, len(sections)+2)
+	used := make(map[*pflag.Flag]bool)
+	for _, sec := range sections {
+		flags := make([]*pflag.Flag, 0, len(sec.Flags))
+		for _, name := range sec.Flags {
+			pf := pfs.Lookup(name)
+			used[pf] = true
+			flags = append(flags, pf)
+		}
+		all = append(all, flagSection{title: sec.Title, flags: flags})
+	}
+
+	global := make([]*pflag.Flag, 0, len(inherited))
+	for _, pf := range inherited {
+		// Skip flags redefined in the subcommand.
+		if pfs.Lookup(pf.Name) == pf && !used[pf] {
+			used[pf] = true
+			global = append(global, pf)
+		}
+	}
+	rest := make([]*pflag.Flag, 0)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		if !used[pf] {
+			rest = append(rest, pf)
+		}
+	})
+	title := "Flags"
+	if len(sections) != 0 {
+		title = "Other flags"
+	}
+	all = append(all, flagSection{title: title, flags: rest})
+	all = append(all, flagSection{title: "Global flags", flags: sortFlags(global)})
+
+	result := make([]flagSection, 0, len(all))
+	for _, sec := range all {
+		visible := make([]*pflag.Flag, 0, len(sec.flags))
+		for _, pf := range sec.flags {
+			if !pf.Hidden {
+				visible = append(visible, pf)
+			}
+		}
+		if len(visible) != 0 {
+			result = append(result, flagSection{title: sec.title, flags: visible})
+		}
+	}
+	return result
+}
+
+// sortFlags sorts the flags by name.
+func sortFlags(flags []*pflag.Flag) []*pflag.Flag {
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+	return flags
+}
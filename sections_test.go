@@ -0,0 +1,79 @@
+package cliff_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestSections_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var a, b bool
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"a": cliff.F(&a, 0, false, ""),
+			"b": cliff.F(&b, 0, false, ""),
+		},
+		Sections: []cliff.Section{{Title: "First", Flags: []string{"a", "c"}}},
+	}
+	err := spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "section First refers to unknown flag: c")
+
+	spec.Sections = []cliff.Section{
+		{Title: "First", Flags: []string{"a", "b"}},
+		{Title: "Second", Flags: []string{"b"}},
+	}
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "flag b is in both sections First and Second")
+
+	spec.Sections = []cliff.Section{{Flags: []string{"a"}}}
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "section title must not be empty")
+
+	spec.Flags["a"] = cliff.F(&a, 0, false, "").Alias("addr").Negatable()
+	spec.Sections = []cliff.Section{{Title: "Net", Flags: []string{"addr"}}}
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "section Net refers to addr, which is alias of --a")
+
+	spec.Sections = []cliff.Section{{Title: "Net", Flags: []string{"no-a"}}}
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "section Net refers to no-a, which is negation of --a")
+}
+
+func TestSections_Global(t *testing.T) {
+	is := is.New(t)
+
+	type Config struct{ verbose, debug, force bool }
+	noop := func(Config) error { return nil }
+	root := cliff.Cmd(func(c *Config) cliff.Spec {
+		return cliff.Spec{
+			Flags: cliff.Flags{
+				"verbose": cliff.F(&c.verbose, 'v', false, "show more output").Persistent(),
+				"debug":   cliff.F(&c.debug, 'd', false, "run in debug mode").Persistent().Hidden(),
+			},
+			Commands: cliff.Commands{
+				"sub": cliff.Cmd(func(c *Config) cliff.Flags {
+					return cliff.Flags{
+						"force": cliff.F(&c.force, 'f', false, "do it anyway"),
+					}
+				}, noop, ""),
+			},
+		}
+	}, noop, "")
+	var buf bytes.Buffer
+	err := cliff.Run(&buf, []string{"example", "sub", "--help"}, root)
+	is.Equal(err.Error(), "pflag: help requested")
+	expected := `Usage: example sub [flags]
+
+Flags:
+  -f, --force   do it anyway
+
+Global flags:
+  -v, --verbose   show more output
+`
+	is.Equal(buf.String(), expected)
+}
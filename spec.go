@@ -0,0 +1,398 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// Definition is a constraint for all types that can describe a CLI.
+type Definition interface {
+	Flags | Spec
+}
+
+// Spec is a full description of a CLI: flags, positional arguments, and so on.
+//
+// Use it instead of [Flags] when you need more than just flags.
+type Spec struct {
+	// Flags is a mapping of CLI flag names to the flags.
+	Flags Flags
+
+	// Args is a list of positional arguments.
+	//
+	// If nil, positional arguments are not checked or parsed.
+	// If not nil, any positional argument not described in Args is an error.
+	Args Args
+
+	// Commands is a mapping of subcommand names to the subcommands.
+	//
+	// Cannot be used together with Args.
+	// Flags of the command must be passed before the subcommand name,
+	// unless they are [Flag.Persistent].
+	Commands Commands
+
+	// EnvPrefix, if not empty, makes every flag to be read also from an env var.
+	//
+	// The env var name is the prefix, underscore, and the flag name in uppercase
+	// with dashes replaced by underscores. For example, "APP_DRY_RUN".
+	// Flags with an explicit [Flag.Env] keep their env var name.
+	EnvPrefix string
+
+	// LookupEnv is used to read env vars. If nil, [os.LookupEnv] is used.
+	LookupEnv func(key string) (string, bool)
+
+	// Sections are titled lists of flags shown together in help.
+	//
+	// Flags are shown in the order they are listed in the section.
+	// Flags not in any section are shown in alphabetical order after all sections.
+	Sections []Section
+
+	// CompletionFlag, if not empty, is the name of the built-in flag
+	// that prints the shell completion script into Stdout.
+	//
+	// The flag accepts the shell name: "bash", "zsh", "fish", or "powershell".
+	// When the flag is passed, [ErrCompletion] is returned.
+	// The same error is returned when the program is called back
+	// by the completion script to complete a flag with [Flag.Complete].
+	CompletionFlag string
+
+	// VersionFlag, if not empty, is the name of the built-in flag
+	// that prints the program version into Stdout.
+	//
+	// When the flag is passed, [ErrVersion] is returned.
+	VersionFlag string
+
+	// Version is the version printed by VersionFlag.
+	//
+	// If empty, the main module version and the VCS revision
+	// are read from the build info, see [debug.ReadBuildInfo].
+	Version string
+
+	// Stdout is used for output explicitly requested by the user,
+	// like shell completion scripts or the version. If nil, [os.Stdout] is used.
+	Stdout io.Writer
+
+	// Usage, if not empty, replaces the generated usage line in help.
+	Usage string
+
+	// Description is the description of the program shown in help after the usage line.
+	Description string
+
+	// Examples are usage examples shown in help after all flags.
+	Examples []Example
+
+	// Epilogue is the text shown at the end of help.
+	Epilogue string
+
+	// Color enables highlighting flag names and default values in help with ANSI colors.
+	//
+	// Colors are used only if help is written into a terminal
+	// and the NO_COLOR env var is not set.
+	Color bool
+
+	// HelpRenderer writes the help message on --help.
+	//
+	// If nil, [DefaultHelp] is used.
+	HelpRenderer HelpRenderer
+
+	// Result, if not nil, is filled with the info about the parsed flags,
+	// like where each flag value came from.
+	Result *Result
+
+	// ExitCodes are used by [MustParse] and [MustRun] to exit on errors.
+	ExitCodes ExitCodes
+
+	// Groups are constraints on which flags can be passed together.
+	//
+	// See [ExactlyOne], [AtMostOne], [AllOrNone], and [Requires].
+	Groups []Group
+
+	// ConfigFlag, if not empty, is the name of the string flag
+	// holding the path to the config file to read flag values from.
+	//
+	// The values from the config file are used only for flags
+	// that are passed neither explicitly nor via env vars.
+	// If the flag value is empty, no config file is read.
+	ConfigFlag string
+
+	// ConfigFormat is used to parse the config file.
+	//
+	// If nil, the format is detected from the file extension:
+	// ".json" for [JSON], ".env" for [DotEnv], and ".ini", ".cfg", ".conf" for [INI].
+	ConfigFormat ConfigFormat
+
+	// ReadFile is used to read the config file. If nil, [os.ReadFile] is used.
+	ReadFile func(path string) ([]byte, error)
+}
+
+// Parse the given arguments.
+//
+// Help and warnings will be written into the given stderr stream.
+// If a subcommand is selected, it will be parsed and its handler will be called.
+//
+// Typical usage:
+//
+//	spec.Parse(os.Stderr, os.Args)
+func (s Spec) Parse(stderr io.Writer, args []string) error {
+	sub, err := s.parse(stderr, args, inherited{})
+	if err != nil {
+		return err
+	}
+	if sub != nil {
+		return sub.run()
+	}
+	if len(s.Commands) != 0 {
+		return MissingCommandError{Commands: s.Commands.names()}
+	}
+	return nil
+}
+
+// parse the given arguments and return the selected subcommand, if any.
+func (s Spec) parse(stderr io.Writer, args []string, inh inherited) (*subcall, error) {
+	if s.Args != nil && len(s.Commands) != 0 {
+		return nil, DefinitionError{Err: errors.New("positional arguments cannot be used together with subcommands")}
+	}
+	err := s.Commands.validate()
+	if err != nil {
+		return nil, defError(err)
+	}
+	pfs, err := s.Flags.PFlagSet(stderr, args[0])
+	if err != nil {
+		return nil, defError(err)
+	}
+	if s.EnvPrefix != "" {
+		err = setEnvPrefix(pfs, s.Flags, s.EnvPrefix)
+		if err != nil {
+			return nil, defError(err)
+		}
+	}
+	err = inherit(pfs, inh.flags)
+	if err != nil {
+		return nil, defError(err)
+	}
+	groups, err := resolveGroups(pfs, s.Groups)
+	if err != nil {
+		return nil, defError(err)
+	}
+	groups = append(groups, inh.groups...)
+	err = validateSections(pfs, s.Sections)
+	if err != nil {
+		return nil, defError(err)
+	}
+	if len(args) > 1 && args[1] == completeArg && s.Flags.hasCompleters() {
+		err = s.Flags.complete(s.stdout(), pfs, args[2:])
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrCompletion
+	}
+	var shell Shell
+	if s.CompletionFlag != "" {
+		err = addCompletionFlag(pfs, s.CompletionFlag, &shell)
+		if err != nil {
+			return nil, defError(err)
+		}
+	}
+	var version bool
+	if s.VersionFlag != "" {
+		err = addVersionFlag(pfs, s.VersionFlag, &version)
+		if err != nil {
+			return nil, defError(err)
+		}
+	}
+	var vals []pflag.Value
+	if s.Args != nil {
+		vals, err = s.Args.values()
+		if err != nil {
+			return nil, defError(err)
+		}
+	}
+	pfs.Usage = func() {
+		writeHelp(stderr, args[0], s, vals, pfs, inh.flags)
+	}
+	if len(s.Commands) != 0 {
+		// Stop at the subcommand name, the rest is parsed by the subcommand.
+		pfs.SetInterspersed(false)
+	}
+	var failed failedSet
+	restore := recordFailures(pfs, &failed)
+	err = pfs.Parse(args[1:])
+	restore()
+	if err != nil {
+		return nil, parseError(pfs, err, failed)
+	}
+	resolveAliases(pfs)
+	err = resolveNegations(pfs)
+	if err != nil {
+		return nil, err
+	}
+	if shell != "" {
+		err = writeCompletion(s.stdout(), shell, programName(args[0]), pfs)
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrCompletion
+	}
+	if version {
+		v := s.Version
+		if v == "" {
+			v = buildVersion()
+		}
+		fmt.Fprintln(s.stdout(), v)
+		return nil, ErrVersion
+	}
+
+	if inh.set == nil {
+		inh.set = make(map[*pflag.Flag]Source)
+	}
+	checks := make(map[*pflag.Flag]func() error)
+	for pf, check := range inh.checks {
+		checks[pf] = check
+	}
+	for name, flag := range s.Flags {
+		if flag.check != nil {
+			checks[pfs.Lookup(name)] = flag.check
+		}
+	}
+	inh.checks = checks
+
+	var sub *subcall
+	var skip []*pflag.Flag
+	if len(s.Commands) != 0 {
+		sub, err = s.selectCommand(stderr, args[0], pfs, inh)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sub != nil {
+		// Persistent flags will be checked and set from env vars
+		// and config files by the subcommand.
+		skip = sub.inherited.flags
+		err = s.deferSources(pfs, sub)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, apply := range inh.sources {
+			err = apply()
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = s.applySources(pfs, s.Flags, inh.set, nil)
+		if err != nil {
+			return nil, err
+		}
+		if s.Result != nil {
+			*s.Result = newResult(pfs, inh.set)
+		}
+		for _, update := range inh.results {
+			update()
+		}
+	}
+	err = checkRequired(pfs, inh.set, skip)
+	if err != nil {
+		return nil, err
+	}
+	err = checkValues(pfs, checks, inh.set, skip)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		// Groups with persistent flags will be checked by the subcommand.
+		if sub != nil && g.uses(skip) {
+			sub.inherited.groups = append(sub.inherited.groups, g)
+			continue
+		}
+		err = g.check(inh.set)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sub != nil || s.Args == nil {
+		return sub, nil
+	}
+	return nil, s.Args.parse(vals, pfs.Args())
+}
+
+// applySources sets values from env vars and config files for the flags not passed explicitly.
+//
+// Env vars are applied only for the given flags. If apply is not nil,
+// config values are applied only for the flags for which it returns true.
+func (s Spec) applySources(pfs *pflag.FlagSet, flags Flags, set map[*pflag.Flag]Source, apply func(*pflag.Flag) bool) error {
+	err := applyEnv(pfs, flags, s.lookupEnv(), set)
+	if err != nil {
+		return err
+	}
+	if s.ConfigFlag != "" {
+		err = s.applyConfig(pfs, set, apply)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deferSources applies env vars and config files to flags that are not passed
+// to the subcommand and defers applying them to persistent flags
+// until the last subcommand is parsed.
+func (s Spec) deferSources(pfs *pflag.FlagSet, sub *subcall) error {
+	inh := &sub.inherited
+	persistent := make(map[*pflag.Flag]bool)
+	for _, pf := range inh.flags {
+		persistent[pf] = true
+	}
+	own := make(Flags)
+	ownPersistent := make(Flags)
+	for name, flag := range s.Flags {
+		if flag.persist {
+			ownPersistent[name] = flag
+		} else {
+			own[name] = flag
+		}
+	}
+	err := s.applySources(pfs, own, inh.set, func(pf *pflag.Flag) bool {
+		return !persistent[pf]
+	})
+	if err != nil {
+		return err
+	}
+	set := inh.set
+	inh.sources = append(inh.sources[:len(inh.sources):len(inh.sources)], func() error {
+		return s.applySources(pfs, ownPersistent, set, func(pf *pflag.Flag) bool {
+			return persistent[pf]
+		})
+	})
+	if s.Result != nil {
+		inh.results = append(inh.results[:len(inh.results):len(inh.results)], func() {
+			*s.Result = newResult(pfs, set)
+		})
+	}
+	return nil
+}
+
+func (s Spec) lookupEnv() func(string) (string, bool) {
+	if s.LookupEnv == nil {
+		return os.LookupEnv
+	}
+	return s.LookupEnv
+}
+
+func (s Spec) stdout() io.Writer {
+	if s.Stdout == nil {
+		return os.Stdout
+	}
+	return s.Stdout
+}
+
+// toSpec converts any [Definition] into [Spec].
+func toSpec[D Definition](def D) Spec {
+	flags, ok := any(def).(Flags)
+	if ok {
+		return Spec{Flags: flags}
+	}
+	return any(def).(Spec)
+}
@@ -0,0 +1,153 @@
+package cliff
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var unknownLong = regexp.MustCompile(`^unknown flag: --(.+)$`).FindStringSubmatch
+var unknownShort = regexp.MustCompile(`^unknown shorthand flag: '(.)' in -(.+)$`).FindStringSubmatch
+
+// suggest returns the candidates that look similar to the given input.
+//
+// The result is sorted by similarity, the most similar first.
+func suggest(input string, candidates []string) []string {
+	type match struct {
+		name string
+		dist int
+	}
+	matches := make([]match, 0)
+	for _, c := range candidates {
+		dist := distance(input, c)
+		if (dist <= 2 && dist < len(input)) || (input != "" && strings.HasPrefix(c, input)) {
+			matches = append(matches, match{name: c, dist: dist})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].name < matches[j].name
+	})
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.name
+	}
+	return result
+}
+
+// distance calculates the optimal string alignment distance between two strings.
+//
+// It's the Levenshtein distance that also counts transposition
+// of two adjacent characters as a single edit.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+	return first
+}
+
+// didYouMean formats suggestions for the input as a hint to append to an error message.
+//
+// Returns an empty string if there are no suggestions.
+func didYouMean(input string, candidates []string) string {
+	matches := suggestTop(input, candidates)
+	if len(matches) == 0 {
+		return ""
+	}
+	return "; did you mean " + strings.Join(matches, " or ") + "?"
+}
+
+// suggestTop returns at most 3 candidates most similar to the input.
+func suggestTop(input string, candidates []string) []string {
+	matches := suggest(input, candidates)
+	if len(matches) > 3 {
+		matches = matches[:3]
+	}
+	return matches
+}
+
+// suggestFlags returns known flags similar to the unknown flag from the pflag error message.
+//
+// Hidden and deprecated flags and deprecated shorthands are not suggested.
+// Aliases and negations are suggested unless the flag they belong to is hidden.
+func suggestFlags(pfs *pflag.FlagSet, msg string) []string {
+	longs := make([]string, 0)
+	shorts := make([]string, 0)
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		if ownerOf(pfs, pf).Hidden || pf.Deprecated != "" {
+			return
+		}
+		longs = append(longs, pf.Name)
+		if pf.Shorthand != "" && pf.ShorthandDeprecated == "" {
+			shorts = append(shorts, pf.Shorthand)
+		}
+	})
+	matches := make([]string, 0)
+	if m := unknownLong(msg); m != nil {
+		// --hots
+		for _, name := range suggest(m[1], longs) {
+			matches = append(matches, "--"+name)
+		}
+		// --p
+		for _, short := range shorts {
+			if short == m[1] {
+				matches = append(matches, "-"+short)
+			}
+		}
+	} else if m := unknownShort(msg); m != nil {
+		if len(m[2]) > 1 && strings.HasPrefix(m[2], m[1]) {
+			// -port
+			for _, name := range suggest(m[2], longs) {
+				matches = append(matches, "--"+name)
+			}
+		} else {
+			// -V
+			for _, short := range shorts {
+				if strings.EqualFold(short, m[1]) {
+					matches = append(matches, "-"+short)
+				}
+			}
+			// -x
+			for _, name := range longs {
+				if name == m[1] {
+					matches = append(matches, "--"+name)
+				}
+			}
+		}
+	}
+	if len(matches) > 3 {
+		matches = matches[:3]
+	}
+	return matches
+}
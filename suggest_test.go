@@ -0,0 +1,63 @@
+package cliff_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestSuggestFlag(t *testing.T) {
+	is := is.New(t)
+
+	var host, hidden, old string
+	var port int
+	var verbose, x bool
+	flags := cliff.Flags{
+		"host":    cliff.F(&host, 'H', "", "").ShortDeprecated("use --host"),
+		"port":    cliff.F(&port, 'p', 0, ""),
+		"post":    cliff.F(&hidden, 0, "", "").Hidden(),
+		"porter":  cliff.F(&old, 0, "", "").Deprecated("use --port"),
+		"verbose": cliff.F(&verbose, 'v', false, ""),
+		"x":       cliff.F(&x, 0, false, ""),
+	}
+	parse := func(args ...string) string {
+		err := flags.Parse(io.Discard, append([]string{"example"}, args...))
+		if err == nil {
+			return ""
+		}
+		return err.Error()
+	}
+
+	is.Equal(parse("--hots"), "unknown flag: --hots; did you mean --host?")
+	is.Equal(parse("--prot", "80"), "unknown flag: --prot; did you mean --port?")
+	is.Equal(parse("--verb"), "unknown flag: --verb; did you mean --verbose?")
+	is.Equal(parse("--v"), "unknown flag: --v; did you mean --verbose or -v?")
+	is.Equal(parse("--h"), "unknown flag: --h; did you mean --host?")
+	is.Equal(parse("--something"), "unknown flag: --something")
+	is.Equal(parse("-oprt"), "unknown shorthand flag: 'o' in -oprt; did you mean --port?")
+	is.Equal(parse("-V"), "unknown shorthand flag: 'V' in -V; did you mean -v?")
+	is.Equal(parse("-x"), "unknown shorthand flag: 'x' in -x; did you mean --x?")
+	is.Equal(parse("-vq"), "unknown shorthand flag: 'q' in -q")
+	is.Equal(parse("-q"), "unknown shorthand flag: 'q' in -q")
+}
+
+func TestSuggestFlag_Companions(t *testing.T) {
+	is := is.New(t)
+
+	var addr, secret string
+	var color bool
+	flags := cliff.Flags{
+		"listen": cliff.F(&addr, 0, "", "").Alias("addr"),
+		"color":  cliff.F(&color, 0, true, "").Negatable(),
+		"secret": cliff.F(&secret, 0, "", "").Hidden().Alias("token"),
+	}
+	parse := func(args ...string) string {
+		return flags.Parse(io.Discard, append([]string{"example"}, args...)).Error()
+	}
+
+	is.Equal(parse("--adr", "x"), "unknown flag: --adr; did you mean --addr?")
+	is.Equal(parse("--no-colr"), "unknown flag: --no-colr; did you mean --no-color?")
+	is.Equal(parse("--tokn", "x"), "unknown flag: --tokn")
+}
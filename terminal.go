@@ -0,0 +1,47 @@
+package cliff
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// defaultWidth is the help width used when the terminal width is unknown.
+const defaultWidth = 80
+
+// helpWidth returns the width of the terminal the help is written into.
+//
+// If the writer isn't a terminal, the COLUMNS env var is used.
+// If it's not set either, [defaultWidth] is used.
+func helpWidth(w io.Writer, lookup func(string) (string, bool)) int {
+	f, ok := w.(*os.File)
+	if ok {
+		width, isTerm := terminalWidth(f)
+		if isTerm && width > 0 {
+			return width
+		}
+	}
+	raw, _ := lookup("COLUMNS")
+	width, err := strconv.Atoi(raw)
+	if err == nil && width > 0 {
+		return width
+	}
+	return defaultWidth
+}
+
+// useColor checks if ANSI colors can be written into the writer.
+//
+// Colors are used only for terminals and only if NO_COLOR env var is not set.
+// See https://no-color.org/.
+func useColor(w io.Writer, lookup func(string) (string, bool)) bool {
+	noColor, _ := lookup("NO_COLOR")
+	if noColor != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	_, isTerm := terminalWidth(f)
+	return isTerm
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package cliff
+
+import "os"
+
+// terminalWidth returns the width of the terminal and false if the file is not a terminal.
+//
+// On this platform, the width is unknown and any character device is considered a terminal.
+func terminalWidth(f *os.File) (int, bool) {
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, false
+	}
+	return 0, stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,151 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Ordered is a constraint for types supporting comparison operators.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 |
+		~string
+}
+
+// Sized is a constraint for types supporting len.
+type Sized interface {
+	~string |
+		~[]bool |
+		~[]byte |
+		~[]float32 | ~[]float64 |
+		~[]int | ~[]int32 | ~[]int64 |
+		~[]net.IP |
+		~[]string |
+		~[]time.Duration |
+		~[]uint |
+		~map[string]int | ~map[string]int64 | ~map[string]string
+}
+
+// InRange creates a validator checking that the value is between lower and upper, inclusive.
+func InRange[T Ordered](lower, upper T) func(T) error {
+	return func(val T) error {
+		if val < lower || val > upper {
+			return fmt.Errorf("must be between %v and %v", lower, upper)
+		}
+		return nil
+	}
+}
+
+// Positive is a validator checking that the value is greater than zero.
+func Positive[T Ordered](val T) error {
+	var zero T
+	if val <= zero {
+		return errors.New("must be positive")
+	}
+	return nil
+}
+
+// OneOf creates a validator checking that the value is one of the given values.
+func OneOf[T comparable](vals ...T) func(T) error {
+	return func(val T) error {
+		for _, v := range vals {
+			if val == v {
+				return nil
+			}
+		}
+		strs := make([]string, len(vals))
+		for i, v := range vals {
+			strs[i] = fmt.Sprint(v)
+		}
+		return fmt.Errorf("must be one of: %s", strings.Join(strs, ", "))
+	}
+}
+
+// Match creates a validator checking that the value matches the regular expression.
+func Match(re *regexp.Regexp) func(string) error {
+	return func(val string) error {
+		if !re.MatchString(val) {
+			return fmt.Errorf("must match %s", re)
+		}
+		return nil
+	}
+}
+
+// NonEmpty is a validator checking that the string, slice, or map is not empty.
+func NonEmpty[T Sized](val T) error {
+	if len(val) == 0 {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+// FileExists is a validator checking that the file or directory with the given path exists.
+func FileExists(path string) error {
+	_, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("file does not exist: %s", path)
+		}
+		return err
+	}
+	return nil
+}
+
+// validateAll creates a function calling all the given validators for the target value.
+func validateAll[T any](val *T, validators []func(T) error) func() error {
+	if len(validators) == 0 {
+		return nil
+	}
+	return func() error {
+		for _, validator := range validators {
+			err := validator(*val)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// checkValues runs validators for all flags in the parsed flag set.
+//
+// The set contains flags set from env vars or config files.
+// Flags that are not set and the flags from skip list are not checked.
+func checkValues(
+	pfs *pflag.FlagSet,
+	checks map[*pflag.Flag]func() error,
+	set map[*pflag.Flag]Source,
+	skip []*pflag.Flag,
+) error {
+	skipped := toSet(skip)
+	var err error
+	pfs.VisitAll(func(pf *pflag.Flag) {
+		check := checks[pf]
+		if err != nil || check == nil || skipped[pf] || flagSource(pf, set) == SourceDefault {
+			return
+		}
+		checkErr := check()
+		if checkErr != nil {
+			err = invalidValue(pf.Name, pf.Value.String(), checkErr)
+		}
+	})
+	return err
+}
+
+// invalidValue creates [InvalidValueError] for a value rejected by a validator.
+func invalidValue(name, val string, err error) InvalidValueError {
+	return InvalidValueError{
+		Name:  name,
+		Value: val,
+		Err:   err,
+		msg:   fmt.Sprintf("invalid value for --%s: %v", name, err),
+	}
+}
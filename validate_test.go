@@ -0,0 +1,95 @@
+package cliff_test
+
+import (
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestValidators(t *testing.T) {
+	is := is.New(t)
+
+	is.NoErr(cliff.InRange(1, 10)(1))
+	is.NoErr(cliff.InRange(1, 10)(10))
+	is.Equal(cliff.InRange(1, 10)(11).Error(), "must be between 1 and 10")
+	is.Equal(cliff.InRange(time.Second, time.Minute)(0).Error(), "must be between 1s and 1m0s")
+
+	is.NoErr(cliff.Positive(time.Second))
+	is.Equal(cliff.Positive(0).Error(), "must be positive")
+	is.Equal(cliff.Positive(-1.5).Error(), "must be positive")
+
+	is.NoErr(cliff.OneOf("a", "b")("b"))
+	is.Equal(cliff.OneOf("a", "b")("c").Error(), "must be one of: a, b")
+
+	is.NoErr(cliff.Match(regexp.MustCompile(`^v\d+$`))("v1"))
+	is.Equal(cliff.Match(regexp.MustCompile(`^v\d+$`))("1").Error(), `must match ^v\d+$`)
+
+	is.NoErr(cliff.NonEmpty("a"))
+	is.NoErr(cliff.NonEmpty([]int{0}))
+	is.Equal(cliff.NonEmpty("").Error(), "must not be empty")
+	is.Equal(cliff.NonEmpty(map[string]string{}).Error(), "must not be empty")
+
+	is.NoErr(cliff.FileExists("validate_test.go"))
+	is.Equal(cliff.FileExists("missing.go").Error(), "file does not exist: missing.go")
+}
+
+func TestF_Validators(t *testing.T) {
+	is := is.New(t)
+
+	var port uint16
+	var name string
+	flags := cliff.Flags{
+		"port": cliff.F(&port, 'p', 8080, "", cliff.InRange[uint16](1024, 49151)),
+		"name": cliff.F(&name, 'n', "", "", cliff.NonEmpty[string], cliff.OneOf("ann", "bob")),
+	}
+	parse := func(args ...string) error {
+		return flags.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+	is.NoErr(parse("-n", "ann"))
+	is.Equal(parse("-n", "ann", "-p", "80").Error(), "invalid value for --port: must be between 1024 and 49151")
+	is.Equal(parse("-n", "").Error(), "invalid value for --name: must not be empty")
+	is.Equal(parse("-n", "tom").Error(), "invalid value for --name: must be one of: ann, bob")
+
+	pfs, err := flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(pfs.Parse([]string{"-n", "ann", "-p", "80"}))
+	is.Equal(flags.CheckPFlagSet(pfs).Error(), "invalid value for --port: must be between 1024 and 49151")
+}
+
+func TestF_Validators_Default(t *testing.T) {
+	is := is.New(t)
+
+	var config string
+	env := map[string]string{}
+	spec := cliff.Spec{
+		Flags: cliff.Flags{
+			"config": cliff.F(&config, 'c', "", "", cliff.FileExists).Env("CONFIG"),
+		},
+		LookupEnv: func(key string) (string, bool) {
+			val, found := env[key]
+			return val, found
+		},
+	}
+	parse := func(args ...string) error {
+		return spec.Parse(io.Discard, append([]string{"example"}, args...))
+	}
+	// The default value isn't validated, so optional flags can have validators.
+	is.NoErr(parse())
+	is.NoErr(parse("-c", "validate_test.go"))
+	is.Equal(parse("-c", "missing.go").Error(), "invalid value for --config: file does not exist: missing.go")
+	env["CONFIG"] = "missing.go"
+	is.Equal(parse().Error(), "invalid value for --config: file does not exist: missing.go")
+
+	pfs, err := spec.Flags.PFlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(pfs.Parse(nil))
+	is.NoErr(spec.Flags.CheckPFlagSet(pfs))
+	gfs, err := spec.Flags.FlagSet(io.Discard, "example")
+	is.NoErr(err)
+	is.NoErr(gfs.Parse(nil))
+	is.NoErr(spec.Flags.CheckFlagSet(gfs))
+}
@@ -0,0 +1,55 @@
+package cliff
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/spf13/pflag"
+)
+
+// ErrVersion is returned when the program version was requested and printed.
+var ErrVersion = errors.New("version requested")
+
+// addVersionFlag adds the built-in flag printing the program version.
+func addVersionFlag(pfs *pflag.FlagSet, name string, version *bool) error {
+	err := validateName(name)
+	if err != nil {
+		return fmt.Errorf("validate flag name (%s): %v", name, err)
+	}
+	if pfs.Lookup(name) != nil {
+		return fmt.Errorf("version flag %s is already defined", name)
+	}
+	flag := F(version, 0, false, "print the program version and exit")
+	return flag.AddTo(pfs, name)
+}
+
+// buildVersion returns the main module version and the VCS revision
+// the binary was built from, like "v1.2.3 (0f1e2d3c, modified)".
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+	version := info.Main.Version
+	if version == "" {
+		version = "(devel)"
+	}
+	revision := ""
+	modified := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision == "" {
+		return version
+	}
+	if modified {
+		revision += ", modified"
+	}
+	return fmt.Sprintf("%s (%s)", version, revision)
+}
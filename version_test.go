@@ -0,0 +1,70 @@
+package cliff_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/orsinium-labs/cliff"
+)
+
+func TestVersionFlag(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	var stdout bytes.Buffer
+	spec := cliff.Spec{
+		Flags:       cliff.Flags{"port": cliff.F(&port, 'p', 8080, "").Required()},
+		VersionFlag: "version",
+		Version:     "v1.2.3",
+		Stdout:      &stdout,
+	}
+
+	// Required flags are not checked when the version is requested.
+	err := spec.Parse(io.Discard, []string{"example", "--version"})
+	is.Equal(err, cliff.ErrVersion)
+	is.Equal(stdout.String(), "v1.2.3\n")
+
+	stdout.Reset()
+	err = spec.Parse(io.Discard, []string{"example", "-p", "80"})
+	is.NoErr(err)
+	is.Equal(stdout.String(), "")
+
+	// The version from the build info.
+	spec.Version = ""
+	err = spec.Parse(io.Discard, []string{"example", "--version"})
+	is.Equal(err, cliff.ErrVersion)
+	is.True(stdout.String() != "")
+
+	var help bytes.Buffer
+	_ = spec.Parse(&help, []string{"example", "--help"})
+	is.True(bytes.Contains(help.Bytes(), []byte("print the program version and exit")))
+}
+
+func TestVersionFlag_Exit(t *testing.T) {
+	is := is.New(t)
+
+	spec := func(c *struct{}) cliff.Spec {
+		return cliff.Spec{VersionFlag: "version", Version: "1.0", Stdout: io.Discard}
+	}
+	code := -1
+	cliff.MustParse(io.Discard, func(c int) { code = c }, []string{"example", "--version"}, spec)
+	is.Equal(code, 0)
+}
+
+func TestVersionFlag_Errors(t *testing.T) {
+	is := is.New(t)
+
+	var port int
+	spec := cliff.Spec{
+		Flags:       cliff.Flags{"version": cliff.F(&port, 0, 0, "")},
+		VersionFlag: "version",
+	}
+	err := spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "version flag version is already defined")
+
+	spec.VersionFlag = "Version"
+	err = spec.Parse(io.Discard, []string{"example"})
+	is.Equal(err.Error(), "validate flag name (Version): must be lowercase")
+}